@@ -16,59 +16,94 @@ type Node[T any] interface {
 	Cost() int
 }
 
+// StepCoster may be implemented by nodes whose traversal cost depends on the node they
+// are reached from, such as nodes connected diagonally. Whenever a node implements it,
+// the cost of moving from it to one of its neighbors is given by StepCost instead of the
+// neighbor's Cost.
+type StepCoster[T any] interface {
+	StepCost(next T) int
+}
+
+// StepCost returns the cost of moving from a node to one of its neighbors. See
+// StepCoster.
+func StepCost[N Node[N]](from, next N) int {
+	if coster, ok := any(from).(StepCoster[N]); ok {
+		return coster.StepCost(next)
+	}
+	return next.Cost()
+}
+
 // Heuristic is a heuristic function used in the A* algorithm implementation. Its return
-// will be used when defining a a node's priority during the search. The higher the
-// returned value, the more likely the node is to be traversed next.
+// is an estimate of the cost of the cheapest path between from and to, and it is added
+// to a node's traversal cost when defining its priority during the search. The lower
+// the resulting sum, the more likely the node is to be traversed next.
 type Heuristic[T any] func(from T, to T) int
 
 // FindPath implements the A* algorithm to find a path from start to goal. The returned
-// slice is the computed path. If no path is found, a nil slice is returned
+// slice is the computed path. If no path is found, a nil slice is returned. The search is
+// carried out by a Searcher, which may be used directly in order to follow its progress.
 func FindPath[N Node[N]](start N, goal N, heuristic Heuristic[N]) ([]N, int) {
+	searcher := NewSearcher(start, goal, heuristic)
+	for searcher.Step() {
+	}
+	return searcher.Path()
+}
+
+// Costs runs Dijkstra's algorithm from start and returns the cost of the cheapest path
+// from start to every reachable node, including start itself.
+func Costs[N Node[N]](start N) map[N]int {
 	var frontier pqueue.PriorityQueue[N]
 	frontier.Push(start, 0)
 
 	costTo := map[N]int{start: 0}
-	cameFrom := map[N]*N{
-		start: nil,
-	}
+	done := make(map[N]struct{})
 
 	for !frontier.Empty() {
 		currentNode := frontier.Pop()
-
-		if currentNode == goal {
-			// build a slice, starting from cameFrom[goal], that specifies the reverse
-			// path (from goal to start)
-			path := []N{goal}
-			previousNode := cameFrom[goal]
-			for {
-				if previousNode == nil {
-					break
-				}
-				path = append(path, *previousNode)
-				previousNode = cameFrom[*previousNode]
-			}
-			// reverse the slice, therefore making the slice point from start to goal
-			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
-				path[i], path[j] = path[j], path[i]
-			}
-			return path, costTo[goal]
+		if _, isDone := done[currentNode]; isDone {
+			continue
 		}
+		done[currentNode] = struct{}{}
 
 		for _, next := range currentNode.Neighbors() {
-			costToNext := costTo[currentNode] + next.Cost()
+			costToNext := costTo[currentNode] + StepCost(currentNode, next)
 			previousCostToNext, isNextVisited := costTo[next]
 
 			if !isNextVisited || costToNext < previousCostToNext {
 				costTo[next] = costToNext
-				// as "cost" represents the traversal cost, the higher the cost, the
-				// lower its priority should be. Therefore, it is turned into a negative
-				// so the higher costs have a lower priority
-				priority := (costToNext * (-1)) + heuristic(next, goal)
-				frontier.Push(next, priority)
-				cameFrom[next] = &currentNode
+				frontier.Push(next, costToNext*(-1))
 			}
 		}
 	}
 
-	return nil, 0
+	return costTo
+}
+
+// buildPath builds a slice, starting from cameFrom[goal], that specifies the reverse
+// path (from goal to start) and then reverses it, making the slice point from start to
+// goal.
+func buildPath[N comparable](cameFrom map[N]*N, goal N) []N {
+	path := []N{goal}
+	previousNode := cameFrom[goal]
+	for {
+		if previousNode == nil {
+			break
+		}
+		path = append(path, *previousNode)
+		previousNode = cameFrom[*previousNode]
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// pathCost returns the traversal cost of path. The first node is where the path starts,
+// therefore its cost is not accounted for.
+func pathCost[N Node[N]](path []N) int {
+	cost := 0
+	for idx := 1; idx < len(path); idx++ {
+		cost += StepCost(path[idx-1], path[idx])
+	}
+	return cost
 }
@@ -69,6 +69,24 @@ func TestFindPath(t *testing.T) {
 	})
 }
 
+func TestCosts(t *testing.T) {
+	grid := [][]int{
+		{0, 1},
+		{3, 1},
+		{0, 5},
+	}
+
+	costs := Costs(intNode{x: 0, y: 0, grid: &grid})
+	want := map[intNode]int{
+		{0, 0, &grid}: 0, {1, 0, &grid}: 1,
+		{0, 1, &grid}: 3, {1, 1, &grid}: 2,
+		{0, 2, &grid}: 3, {1, 2, &grid}: 7,
+	}
+	if !reflect.DeepEqual(costs, want) {
+		t.Fatalf("Unexpected costs: %v", costs)
+	}
+}
+
 // ======================================================================================
 // interface implementations
 
@@ -0,0 +1,180 @@
+package astar
+
+import (
+	"sort"
+
+	"github.com/agstrc/heuristic-search/pqueue"
+)
+
+// This file exports search modes which trade the optimality of FindPath for a bounded
+// memory usage. Both report whether the returned path is known to be optimal, which is
+// only the case when no node had to be discarded during the search and the heuristic
+// never overestimates the cost to reach the goal.
+
+// scoredNode is a node paired with the cost used to rank it against its peers.
+type scoredNode[N any] struct {
+	node  N
+	score int
+}
+
+// BeamSearch searches for a path from start to goal by expanding the graph one depth at
+// a time. At each depth, only the width nodes with the lowest estimated total cost are
+// kept, and the remaining ones are discarded. The returned values are the computed path,
+// its cost and whether it is known to be optimal. If no path is found, a nil slice is
+// returned.
+//
+// BeamSearch panics if width is lower than one.
+func BeamSearch[N Node[N]](start N, goal N, heuristic Heuristic[N], width int) ([]N, int, bool) {
+	if width < 1 {
+		panic("beam width must be positive")
+	}
+
+	costTo := map[N]int{start: 0}
+	cameFrom := map[N]*N{
+		start: nil,
+	}
+	found, pruned := start == goal, false
+
+	layer := []N{start}
+	for len(layer) > 0 {
+		// candidates holds the next layer. A node may be reached more than once from
+		// within the same layer, so its index is kept in order to update its score.
+		var candidates []scoredNode[N]
+		candidateIndex := make(map[N]int)
+
+		for _, node := range layer {
+			currentNode := node
+			if currentNode == goal {
+				// the goal is never expanded; it only bounds the remaining search
+				continue
+			}
+
+			for _, next := range currentNode.Neighbors() {
+				costToNext := costTo[currentNode] + StepCost(currentNode, next)
+				if found && costToNext >= costTo[goal] {
+					continue
+				}
+				previousCostToNext, isNextVisited := costTo[next]
+				if isNextVisited && costToNext >= previousCostToNext {
+					continue
+				}
+
+				costTo[next] = costToNext
+				cameFrom[next] = &currentNode
+				if next == goal {
+					found = true
+					continue
+				}
+
+				score := costToNext + heuristic(next, goal)
+				if idx, ok := candidateIndex[next]; ok {
+					candidates[idx].score = score
+				} else {
+					candidateIndex[next] = len(candidates)
+					candidates = append(candidates, scoredNode[N]{node: next, score: score})
+				}
+			}
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].score < candidates[j].score
+		})
+		if found {
+			// candidates which cannot lead to a cheaper path are dropped without any
+			// loss of optimality
+			for idx, candidate := range candidates {
+				if candidate.score >= costTo[goal] {
+					candidates = candidates[:idx]
+					break
+				}
+			}
+		}
+		if len(candidates) > width {
+			candidates = candidates[:width]
+			pruned = true
+		}
+
+		layer = layer[:0]
+		for _, candidate := range candidates {
+			layer = append(layer, candidate.node)
+		}
+	}
+
+	if !found {
+		return nil, 0, false
+	}
+	path := buildPath(cameFrom, goal)
+	return path, pathCost(path), !pruned
+}
+
+// frontierEntry is an entry of a bounded frontier. costTo stores the cost at which node
+// was pushed, which allows stale entries to be told apart from current ones.
+type frontierEntry[N any] struct {
+	node   N
+	costTo int
+}
+
+// BoundedSearch implements the A* algorithm to find a path from start to goal, but it
+// never holds more than limit nodes in its frontier. Whenever the limit is exceeded, the
+// node with the highest estimated total cost is discarded. The returned values are the
+// computed path, its cost and whether it is known to be optimal. If no path is found, a
+// nil slice is returned.
+//
+// BoundedSearch panics if limit is lower than one.
+func BoundedSearch[N Node[N]](start N, goal N, heuristic Heuristic[N], limit int) ([]N, int, bool) {
+	if limit < 1 {
+		panic("frontier limit must be positive")
+	}
+
+	var frontier pqueue.PriorityQueue[frontierEntry[N]]
+	frontier.Push(frontierEntry[N]{node: start, costTo: 0}, 0)
+
+	costTo := map[N]int{start: 0}
+	cameFrom := map[N]*N{
+		start: nil,
+	}
+	expanded := make(map[N]struct{})
+	dropped := false
+
+	for !frontier.Empty() {
+		entry := frontier.Pop()
+		currentNode := entry.node
+		if cost, ok := costTo[currentNode]; !ok || cost != entry.costTo {
+			// a cheaper path to this node has been found since it was pushed
+			continue
+		}
+
+		if currentNode == goal {
+			path := buildPath(cameFrom, goal)
+			return path, pathCost(path), !dropped
+		}
+		expanded[currentNode] = struct{}{}
+
+		for _, next := range currentNode.Neighbors() {
+			costToNext := costTo[currentNode] + StepCost(currentNode, next)
+			previousCostToNext, isNextVisited := costTo[next]
+
+			if !isNextVisited || costToNext < previousCostToNext {
+				costTo[next] = costToNext
+				priority := (costToNext + heuristic(next, goal)) * (-1)
+				frontier.Push(frontierEntry[N]{node: next, costTo: costToNext}, priority)
+				cameFrom[next] = &currentNode
+			}
+		}
+
+		for frontier.Len() > limit {
+			discarded := frontier.PopLowest()
+			dropped = true
+
+			// a node which has never been expanded is forgotten, so it may be found
+			// again through another path. Expanded nodes are kept, as other nodes
+			// already refer to them as their parents.
+			_, isExpanded := expanded[discarded.node]
+			if !isExpanded && costTo[discarded.node] == discarded.costTo {
+				delete(costTo, discarded.node)
+			}
+		}
+	}
+
+	return nil, 0, false
+}
@@ -0,0 +1,114 @@
+package astar
+
+import (
+	"reflect"
+	"testing"
+)
+
+// beamGrid is a grid on which the cheapest path requires a detour through nodes which
+// seem worse in the beginning.
+var beamGrid = [][]int{
+	{0, 1, 9, 9},
+	{1, 1, 9, 1},
+	{1, 9, 9, 1},
+	{1, 1, 1, 1},
+}
+
+// manhattan is a heuristic which never overestimates the cost of a path on beamGrid, as
+// the cheapest traversable block costs 1.
+func manhattan(from, to intNode) int {
+	xDistance := from.x - to.x
+	yDistance := from.y - to.y
+	if xDistance < 0 {
+		xDistance = -xDistance
+	}
+	if yDistance < 0 {
+		yDistance = -yDistance
+	}
+	return xDistance + yDistance
+}
+
+func TestBeamSearch(t *testing.T) {
+	grid := beamGrid
+	start := intNode{x: 0, y: 0, grid: &grid}
+	goal := intNode{x: 3, y: 1, grid: &grid}
+
+	wantPath, wantCost := FindPath(start, goal, manhattan)
+
+	t.Run("wide", func(t *testing.T) {
+		path, cost, optimal := BeamSearch(start, goal, manhattan, 16)
+		if !optimal {
+			t.Fatal("Expected optimal path")
+		}
+		if cost != wantCost {
+			t.Fatalf("Expected cost %d, got %d", wantCost, cost)
+		}
+		if !reflect.DeepEqual(path, wantPath) {
+			t.Fatal("Returned path differs from expected")
+		}
+	})
+
+	t.Run("narrow", func(t *testing.T) {
+		path, cost, optimal := BeamSearch(start, goal, manhattan, 1)
+		if optimal {
+			t.Fatal("Expected path not to be known as optimal")
+		}
+		want := []intNode{
+			{x: 0, y: 0, grid: &grid}, {x: 1, y: 0, grid: &grid}, {x: 1, y: 1, grid: &grid},
+			{x: 2, y: 1, grid: &grid}, {x: 3, y: 1, grid: &grid},
+		}
+		if cost != 12 {
+			t.Fatalf("Expected cost 12, got %d", cost)
+		}
+		if !reflect.DeepEqual(path, want) {
+			t.Fatalf("Expected path %v, got %v", want, path)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		grid := [][]bool{
+			{true, false, true},
+		}
+		start := boolNode{x: 0, y: 0, grid: &grid}
+		goal := boolNode{x: 2, y: 0, grid: &grid}
+
+		path, _, _ := BeamSearch(start, goal, func(_, _ boolNode) int { return 0 }, 2)
+		if path != nil {
+			t.Fatal("Expected nil path")
+		}
+	})
+}
+
+func TestBoundedSearch(t *testing.T) {
+	grid := beamGrid
+	start := intNode{x: 0, y: 0, grid: &grid}
+	goal := intNode{x: 3, y: 1, grid: &grid}
+
+	wantPath, wantCost := FindPath(start, goal, manhattan)
+
+	t.Run("unbounded", func(t *testing.T) {
+		path, cost, optimal := BoundedSearch(start, goal, manhattan, 64)
+		if !optimal {
+			t.Fatal("Expected optimal path")
+		}
+		if cost != wantCost {
+			t.Fatalf("Expected cost %d, got %d", wantCost, cost)
+		}
+		if !reflect.DeepEqual(path, wantPath) {
+			t.Fatal("Returned path differs from expected")
+		}
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		path, cost, optimal := BoundedSearch(start, goal, manhattan, 1)
+		if optimal {
+			t.Fatal("Expected path not to be known as optimal")
+		}
+		if path == nil {
+			t.Fatal("Expected non nil path")
+		}
+		if cost < wantCost {
+			t.Fatal("Cost is lower than the optimal cost")
+		}
+	})
+}
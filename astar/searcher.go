@@ -0,0 +1,181 @@
+package astar
+
+import (
+	"encoding/json"
+
+	"github.com/agstrc/heuristic-search/pqueue"
+)
+
+// Scores are the values the A* algorithm associates with a node during a search.
+type Scores struct {
+	// G is the cost of the cheapest known path from the start to the node.
+	G int `json:"g"`
+	// H is the heuristic's estimate of the cost from the node to the goal.
+	H int `json:"h"`
+	// F is the node's estimated total cost, which is the sum of G and H.
+	F int `json:"f"`
+}
+
+// Searcher is a step-by-step implementation of the A* algorithm. Each call to Step
+// expands a single node, so the state of the search may be inspected in between, which
+// is useful when visualizing how the search progresses.
+type Searcher[N Node[N]] struct {
+	goal      N
+	heuristic Heuristic[N]
+
+	frontier pqueue.PriorityQueue[frontierEntry[N]]
+	costTo   map[N]int
+	cameFrom map[N]*N
+	// reached holds every node reached so far, in the order in which it was first
+	// reached.
+	reached []N
+
+	// open and closed are the sets of nodes waiting to be expanded and of nodes already
+	// expanded, respectively.
+	open   map[N]struct{}
+	closed map[N]struct{}
+
+	// best is the expanded node with the lowest heuristic estimate, which is stored in
+	// bestEstimate.
+	best         N
+	bestEstimate int
+
+	done  bool
+	found bool
+	// steps is the amount of nodes expanded so far.
+	steps int
+
+	// trace is where trace events are written to. It is nil unless tracing is enabled.
+	trace    *json.Encoder
+	traceErr error
+}
+
+// NewSearcher returns a Searcher ready to search for a path from start to goal.
+func NewSearcher[N Node[N]](start N, goal N, heuristic Heuristic[N]) *Searcher[N] {
+	searcher := Searcher[N]{
+		goal: goal, heuristic: heuristic,
+		costTo:   map[N]int{start: 0},
+		cameFrom: map[N]*N{start: nil},
+		reached:  []N{start},
+		open:     map[N]struct{}{start: {}},
+		closed:   make(map[N]struct{}),
+		best:     start, bestEstimate: heuristic(start, goal),
+	}
+	searcher.frontier.Push(frontierEntry[N]{node: start, costTo: 0}, 0)
+
+	return &searcher
+}
+
+// Step expands the next node in the search. It reports whether the search may go on,
+// which is no longer the case after the goal is reached or after every reachable node
+// has been expanded.
+func (s *Searcher[N]) Step() bool {
+	if s.done {
+		return false
+	}
+
+	for !s.frontier.Empty() {
+		entry := s.frontier.Pop()
+		currentNode := entry.node
+		if _, isOpen := s.open[currentNode]; !isOpen || s.costTo[currentNode] != entry.costTo {
+			// a cheaper path to this node has been found since it was pushed
+			continue
+		}
+
+		delete(s.open, currentNode)
+		s.closed[currentNode] = struct{}{}
+		s.traceExpansion(currentNode)
+		s.steps++
+
+		if currentNode == s.goal {
+			s.best = currentNode
+			s.done, s.found = true, true
+			s.traceDone()
+			return false
+		}
+		if estimate := s.heuristic(currentNode, s.goal); estimate < s.bestEstimate {
+			s.best, s.bestEstimate = currentNode, estimate
+		}
+
+		for _, next := range currentNode.Neighbors() {
+			costToNext := s.costTo[currentNode] + StepCost(currentNode, next)
+			previousCostToNext, isNextVisited := s.costTo[next]
+
+			if !isNextVisited || costToNext < previousCostToNext {
+				if !isNextVisited {
+					s.reached = append(s.reached, next)
+				}
+				s.costTo[next] = costToNext
+				// as "cost" represents the traversal cost, the higher the estimated
+				// total cost, the lower its priority should be. Therefore, it is turned
+				// into a negative so the higher costs have a lower priority
+				priority := (costToNext + s.heuristic(next, s.goal)) * (-1)
+				s.frontier.Push(frontierEntry[N]{node: next, costTo: costToNext}, priority)
+				s.cameFrom[next] = &currentNode
+
+				s.open[next] = struct{}{}
+				delete(s.closed, next)
+			}
+		}
+		return true
+	}
+
+	s.done = true
+	s.traceDone()
+	return false
+}
+
+// Done reports whether the search is over.
+func (s *Searcher[N]) Done() bool {
+	return s.done
+}
+
+// Found reports whether the goal has been reached.
+func (s *Searcher[N]) Found() bool {
+	return s.found
+}
+
+// Path returns the path from start to goal and its cost. If the goal has not been
+// reached, a nil slice is returned.
+func (s *Searcher[N]) Path() ([]N, int) {
+	if !s.found {
+		return nil, 0
+	}
+	return buildPath(s.cameFrom, s.goal), s.costTo[s.goal]
+}
+
+// BestPath returns the path from start to the expanded node which is estimated to be the
+// closest to the goal, along with its cost. Once the goal is reached, BestPath returns
+// the same values as Path.
+func (s *Searcher[N]) BestPath() ([]N, int) {
+	return buildPath(s.cameFrom, s.best), s.costTo[s.best]
+}
+
+// Open returns the nodes waiting to be expanded, in no particular order.
+func (s *Searcher[N]) Open() []N {
+	return setSlice(s.open)
+}
+
+// Closed returns the nodes which have already been expanded, in no particular order.
+func (s *Searcher[N]) Closed() []N {
+	return setSlice(s.closed)
+}
+
+// Scores returns the scores of node. If the node has not been reached by the search, the
+// returned bool is false.
+func (s *Searcher[N]) Scores(node N) (Scores, bool) {
+	g, ok := s.costTo[node]
+	if !ok {
+		return Scores{}, false
+	}
+	h := s.heuristic(node, s.goal)
+	return Scores{G: g, H: h, F: g + h}, true
+}
+
+func setSlice[N comparable](set map[N]struct{}) []N {
+	slice := make([]N, 0, len(set))
+	for node := range set {
+		slice = append(slice, node)
+	}
+	return slice
+}
@@ -0,0 +1,103 @@
+package astar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearcher(t *testing.T) {
+	grid := beamGrid
+	start := intNode{x: 0, y: 0, grid: &grid}
+	goal := intNode{x: 3, y: 1, grid: &grid}
+
+	searcher := NewSearcher(start, goal, manhattan)
+	if open := searcher.Open(); !reflect.DeepEqual(open, []intNode{start}) {
+		t.Fatal("Expected only the start to be open")
+	}
+
+	if !searcher.Step() {
+		t.Fatal("Expected search to go on after a single step")
+	}
+	if closed := searcher.Closed(); !reflect.DeepEqual(closed, []intNode{start}) {
+		t.Fatal("Expected only the start to be closed")
+	}
+	if len(searcher.Open()) != 2 {
+		t.Fatalf("Expected 2 open nodes, got %d", len(searcher.Open()))
+	}
+
+	scores, ok := searcher.Scores(intNode{x: 1, y: 0, grid: &grid})
+	if !ok {
+		t.Fatal("Expected neighbor to have been reached")
+	}
+	if scores != (Scores{G: 1, H: 3, F: 4}) {
+		t.Fatalf("Unexpected scores: %+v", scores)
+	}
+	if _, ok := searcher.Scores(goal); ok {
+		t.Fatal("Expected goal not to have been reached")
+	}
+	if path, _ := searcher.Path(); path != nil {
+		t.Fatal("Expected nil path before reaching the goal")
+	}
+
+	steps := 1
+	for searcher.Step() {
+		steps++
+
+		partial, _ := searcher.BestPath()
+		if partial[0] != start {
+			t.Fatal("Expected partial path to begin at the start")
+		}
+	}
+	if !searcher.Done() || !searcher.Found() {
+		t.Fatal("Expected search to be over with the goal found")
+	}
+	if searcher.Step() {
+		t.Fatal("Expected no steps after the search is over")
+	}
+
+	wantPath, wantCost := FindPath(start, goal, manhattan)
+	path, cost := searcher.Path()
+	if cost != wantCost || !reflect.DeepEqual(path, wantPath) {
+		t.Fatal("Returned path differs from expected")
+	}
+	bestPath, _ := searcher.BestPath()
+	if !reflect.DeepEqual(path, bestPath) {
+		t.Fatal("Expected best path to be the final path")
+	}
+	if steps > len(searcher.Closed()) {
+		t.Fatalf("Took %d steps but closed %d nodes", steps, len(searcher.Closed()))
+	}
+}
+
+// TestSearcherRanking checks that nodes are expanded in increasing order of their
+// estimated total cost, which holds for a consistent heuristic such as manhattan.
+func TestSearcherRanking(t *testing.T) {
+	grid := beamGrid
+	start := intNode{x: 0, y: 0, grid: &grid}
+	goal := intNode{x: 3, y: 1, grid: &grid}
+
+	searcher := NewSearcher(start, goal, manhattan)
+	closed := map[intNode]struct{}{}
+	lastF := 0
+	for {
+		more := searcher.Step()
+		for _, node := range searcher.Closed() {
+			if _, ok := closed[node]; ok {
+				continue
+			}
+			closed[node] = struct{}{}
+
+			scores, _ := searcher.Scores(node)
+			if scores.F < lastF {
+				t.Fatalf("Node %v with f %d expanded after f %d", node, scores.F, lastF)
+			}
+			lastF = scores.F
+		}
+		if !more {
+			break
+		}
+	}
+	if len(closed) != 11 {
+		t.Fatalf("Expected 11 expanded nodes, got %d", len(closed))
+	}
+}
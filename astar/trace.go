@@ -0,0 +1,173 @@
+package astar
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// This file exports the means to dump a search for later inspection. A Searcher may
+// write its expansions as JSON Lines events, and its search tree may be exported to the
+// Graphviz DOT format or drawn as a grid of explored nodes.
+
+// Event kinds written to a search trace.
+const (
+	// EventExpand is written whenever a node is expanded.
+	EventExpand = "expand"
+	// EventDone is written once the search is over.
+	EventDone = "done"
+)
+
+// Event is a single line of a search trace. Nodes are encoded through encoding/json,
+// therefore node types may implement json.Marshaler in order to control how they show
+// up in the trace.
+type Event[N any] struct {
+	Kind string `json:"event"`
+	// Step is the amount of nodes expanded before the event.
+	Step int `json:"step"`
+
+	// Node, Parent and Scores are only set for EventExpand. Parent is nil for the start.
+	Node   *N      `json:"node,omitempty"`
+	Parent *N      `json:"parent,omitempty"`
+	Scores *Scores `json:"scores,omitempty"`
+
+	// Found and Cost are only set for EventDone.
+	Found *bool `json:"found,omitempty"`
+	Cost  *int  `json:"cost,omitempty"`
+}
+
+// Trace makes the searcher write an Event to w, as a JSON line, for every following
+// expansion and once the search is over. Tracing stops at the first write error, which
+// is then reported by TraceErr.
+func (s *Searcher[N]) Trace(w io.Writer) {
+	s.trace = json.NewEncoder(w)
+	s.traceErr = nil
+}
+
+// TraceErr returns the error which interrupted the searcher's trace, if any.
+func (s *Searcher[N]) TraceErr() error {
+	return s.traceErr
+}
+
+// traceExpansion writes an EventExpand for node, which must have been reached.
+func (s *Searcher[N]) traceExpansion(node N) {
+	if s.trace == nil {
+		return
+	}
+	scores, _ := s.Scores(node)
+	s.writeEvent(Event[N]{
+		Kind: EventExpand, Step: s.steps,
+		Node: &node, Parent: s.cameFrom[node], Scores: &scores,
+	})
+}
+
+// traceDone writes an EventDone.
+func (s *Searcher[N]) traceDone() {
+	if s.trace == nil {
+		return
+	}
+	found, cost := s.found, 0
+	if found {
+		cost = s.costTo[s.goal]
+	}
+	s.writeEvent(Event[N]{Kind: EventDone, Step: s.steps, Found: &found, Cost: &cost})
+}
+
+func (s *Searcher[N]) writeEvent(event Event[N]) {
+	if err := s.trace.Encode(event); err != nil {
+		s.trace, s.traceErr = nil, fmt.Errorf("failed to write trace event: %w", err)
+	}
+}
+
+// WriteDOT writes the searcher's current search tree to w in the Graphviz DOT format.
+// Each reached node is labeled through label, followed by its scores. Expanded nodes are
+// filled and the nodes of the best path are outlined. Nodes are written in the order in
+// which the search first reached them, so equal searches produce equal outputs as long
+// as the nodes' neighbors are listed in a stable order.
+func (s *Searcher[N]) WriteDOT(w io.Writer, label func(N) string) error {
+	nodes := s.reached
+	ids := make(map[N]int, len(nodes))
+	for idx, node := range nodes {
+		ids[node] = idx
+	}
+
+	bestPath, _ := s.BestPath()
+	onPath := make(map[N]struct{}, len(bestPath))
+	for _, node := range bestPath {
+		onPath[node] = struct{}{}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph search {")
+	fmt.Fprintln(bw, "\tnode [shape=box];")
+	for idx, node := range nodes {
+		scores, _ := s.Scores(node)
+		attributes := fmt.Sprintf("label=%q", fmt.Sprintf(
+			"%s\ng=%d h=%d f=%d", label(node), scores.G, scores.H, scores.F,
+		))
+		if _, isClosed := s.closed[node]; isClosed {
+			attributes += ", style=filled, fillcolor=lightgray"
+		}
+		if _, isOnPath := onPath[node]; isOnPath {
+			attributes += ", color=red, penwidth=2"
+		}
+		fmt.Fprintf(bw, "\tn%d [%s];\n", idx, attributes)
+	}
+	for idx, node := range nodes {
+		if parent := s.cameFrom[node]; parent != nil {
+			fmt.Fprintf(bw, "\tn%d -> n%d;\n", ids[*parent], idx)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// Characters used by WriteGrid.
+const (
+	GridUnreached = '.'
+	GridOpen      = 'o'
+	GridClosed    = 'x'
+	GridPath      = '*'
+)
+
+// WriteGrid draws the nodes explored by the searcher as a grid of width by height
+// characters, one row per line. position maps each node to its column and row; nodes
+// placed outside of the grid are left out. Nodes of the best path are drawn as GridPath,
+// the remaining expanded nodes as GridClosed, nodes waiting to be expanded as GridOpen
+// and every other position as GridUnreached.
+func (s *Searcher[N]) WriteGrid(w io.Writer, width, height int, position func(N) (x, y int)) error {
+	rows := make([][]byte, height)
+	for y := range rows {
+		rows[y] = make([]byte, width+1)
+		for x := 0; x < width; x++ {
+			rows[y][x] = GridUnreached
+		}
+		rows[y][width] = '\n'
+	}
+
+	set := func(node N, char byte) {
+		x, y := position(node)
+		if x >= 0 && x < width && y >= 0 && y < height {
+			rows[y][x] = char
+		}
+	}
+	for node := range s.open {
+		set(node, GridOpen)
+	}
+	for node := range s.closed {
+		set(node, GridClosed)
+	}
+	bestPath, _ := s.BestPath()
+	for _, node := range bestPath {
+		set(node, GridPath)
+	}
+
+	for _, row := range rows {
+		if _, err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package astar
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	grid := beamGrid
+	start := intNode{x: 0, y: 0, grid: &grid}
+	goal := intNode{x: 3, y: 1, grid: &grid}
+
+	var buffer bytes.Buffer
+	searcher := NewSearcher(start, goal, manhattan)
+	searcher.Trace(&buffer)
+	for searcher.Step() {
+	}
+	if err := searcher.TraceErr(); err != nil {
+		t.Fatal("Unexpected trace error:", err)
+	}
+
+	var events []Event[json.RawMessage]
+	scanner := bufio.NewScanner(&buffer)
+	for scanner.Scan() {
+		var event Event[json.RawMessage]
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatal("Failed to unmarshal event:", err)
+		}
+		events = append(events, event)
+	}
+
+	if len(events) != len(searcher.Closed())+1 {
+		t.Fatalf("Expected %d events, got %d", len(searcher.Closed())+1, len(events))
+	}
+	first := events[0]
+	if first.Kind != EventExpand || first.Step != 0 || first.Parent != nil {
+		t.Fatalf("Unexpected first event: %+v", first)
+	}
+	if *first.Scores != (Scores{G: 0, H: 4, F: 4}) {
+		t.Fatalf("Unexpected scores on first event: %+v", *first.Scores)
+	}
+	for _, event := range events[1 : len(events)-1] {
+		if event.Kind != EventExpand || event.Parent == nil || event.Scores == nil {
+			t.Fatalf("Unexpected expansion event: %+v", event)
+		}
+	}
+
+	_, cost := searcher.Path()
+	last := events[len(events)-1]
+	if last.Kind != EventDone || !*last.Found || *last.Cost != cost {
+		t.Fatalf("Unexpected last event: %+v", last)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	grid := beamGrid
+	start := intNode{x: 0, y: 0, grid: &grid}
+	goal := intNode{x: 3, y: 1, grid: &grid}
+
+	searcher := NewSearcher(start, goal, manhattan)
+	for searcher.Step() {
+	}
+
+	var buffer bytes.Buffer
+	label := func(node intNode) string { return fmt.Sprintf("(%d, %d)", node.x, node.y) }
+	if err := searcher.WriteDOT(&buffer, label); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	dot := buffer.String()
+
+	if !strings.HasPrefix(dot, "digraph search {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Fatal("Output is not a DOT digraph")
+	}
+	nodes, edges := strings.Count(dot, "[label="), strings.Count(dot, " -> ")
+	if edges != nodes-1 {
+		t.Fatalf("Expected a tree, got %d nodes and %d edges", nodes, edges)
+	}
+	if !strings.Contains(dot, `n0 [label="(0, 0)\ng=0 h=4 f=4"`) {
+		t.Fatal("Expected start node to be written first")
+	}
+
+	// nodes whose labels are equal are still written in the same order every time
+	same := func(intNode) string { return "node" }
+	var first bytes.Buffer
+	if err := searcher.WriteDOT(&first, same); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	for attempt := 0; attempt < 10; attempt++ {
+		var again bytes.Buffer
+		if err := searcher.WriteDOT(&again, same); err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+		if again.String() != first.String() {
+			t.Fatal("Expected equal searches to produce equal outputs")
+		}
+	}
+}
+
+func TestWriteGrid(t *testing.T) {
+	grid := [][]int{
+		{1, 1, 1},
+		{1, 1, 1},
+	}
+	start := intNode{x: 0, y: 0, grid: &grid}
+	goal := intNode{x: 2, y: 0, grid: &grid}
+
+	searcher := NewSearcher(start, goal, manhattan)
+	for searcher.Step() {
+	}
+
+	var buffer bytes.Buffer
+	position := func(node intNode) (int, int) { return node.x, node.y }
+	if err := searcher.WriteGrid(&buffer, 3, 2, position); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	want := "***\noo.\n"
+	if buffer.String() != want {
+		t.Fatalf("Expected grid %q, got %q", want, buffer.String())
+	}
+}
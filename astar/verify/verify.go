@@ -0,0 +1,122 @@
+// Package verify checks whether heuristics are admissible and consistent on the graphs
+// they are meant for. It is mostly intended to be used from tests.
+package verify
+
+import (
+	"testing"
+
+	"github.com/agstrc/heuristic-search/astar"
+)
+
+// Overestimate is a pair of nodes on which a heuristic is not admissible, as its
+// estimate exceeds the cost of the cheapest path between them.
+type Overestimate[N any] struct {
+	From, To N
+	// Estimate is the heuristic's estimate and Cost is the actual cost.
+	Estimate, Cost int
+}
+
+// Inconsistency is an edge on which a heuristic is not consistent towards Goal. That is,
+// the estimate from From is greater than the cost to reach Next plus the estimate from
+// Next.
+type Inconsistency[N any] struct {
+	From, Next, Goal N
+	// Estimate is the estimate from From, StepCost is the cost to go from From to Next
+	// and NextEstimate is the estimate from Next.
+	Estimate, StepCost, NextEstimate int
+}
+
+// Report lists every violation found by Check.
+type Report[N any] struct {
+	Overestimates   []Overestimate[N]
+	Inconsistencies []Inconsistency[N]
+}
+
+// OK reports whether the heuristic is both admissible and consistent.
+func (r Report[N]) OK() bool {
+	return len(r.Overestimates) == 0 && len(r.Inconsistencies) == 0
+}
+
+// Check checks heuristic on the graph made of all nodes reachable from roots. The
+// cheapest path between every pair of nodes is computed through the Dijkstra algorithm
+// and compared to the heuristic's estimate, and every edge is checked for consistency
+// towards every node. As such, Check is only meant for small graphs.
+func Check[N astar.Node[N]](heuristic astar.Heuristic[N], roots ...N) Report[N] {
+	var report Report[N]
+	nodes := reachable(roots)
+
+	for _, from := range nodes {
+		costTo := astar.Costs(from)
+		for _, to := range nodes {
+			cost, isReachable := costTo[to]
+			if !isReachable {
+				continue
+			}
+			if estimate := heuristic(from, to); estimate > cost {
+				report.Overestimates = append(report.Overestimates, Overestimate[N]{
+					From: from, To: to, Estimate: estimate, Cost: cost,
+				})
+			}
+		}
+	}
+
+	for _, goal := range nodes {
+		for _, from := range nodes {
+			estimate := heuristic(from, goal)
+			for _, next := range from.Neighbors() {
+				nextEstimate := heuristic(next, goal)
+				stepCost := astar.StepCost(from, next)
+				if estimate > stepCost+nextEstimate {
+					report.Inconsistencies = append(report.Inconsistencies, Inconsistency[N]{
+						From: from, Next: next, Goal: goal,
+						Estimate: estimate, StepCost: stepCost, NextEstimate: nextEstimate,
+					})
+				}
+			}
+		}
+	}
+
+	return report
+}
+
+// Heuristic runs Check and reports every violation it finds as an error on tb.
+func Heuristic[N astar.Node[N]](tb testing.TB, heuristic astar.Heuristic[N], roots ...N) {
+	tb.Helper()
+
+	report := Check(heuristic, roots...)
+	for _, o := range report.Overestimates {
+		tb.Errorf(
+			"heuristic overestimates from %v to %v: estimate is %d, cost is %d",
+			o.From, o.To, o.Estimate, o.Cost,
+		)
+	}
+	for _, i := range report.Inconsistencies {
+		tb.Errorf(
+			"heuristic is inconsistent from %v to %v towards %v: %d > %d + %d",
+			i.From, i.Next, i.Goal, i.Estimate, i.StepCost, i.NextEstimate,
+		)
+	}
+}
+
+// reachable returns every node reachable from roots, in breadth-first order.
+func reachable[N astar.Node[N]](roots []N) []N {
+	var nodes []N
+	seen := make(map[N]struct{})
+	for _, root := range roots {
+		if _, isSeen := seen[root]; isSeen {
+			continue
+		}
+		seen[root] = struct{}{}
+		nodes = append(nodes, root)
+	}
+
+	for idx := 0; idx < len(nodes); idx++ {
+		for _, next := range nodes[idx].Neighbors() {
+			if _, isSeen := seen[next]; !isSeen {
+				seen[next] = struct{}{}
+				nodes = append(nodes, next)
+			}
+		}
+	}
+	return nodes
+}
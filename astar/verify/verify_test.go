@@ -0,0 +1,85 @@
+package verify
+
+import "testing"
+
+// lineNode is a node on a line of blocks. Each block costs 10 to be entered, except for
+// the last one, which costs 1.
+type lineNode struct {
+	position, length int
+}
+
+func (l lineNode) Neighbors() []lineNode {
+	var neighbors []lineNode
+	if l.position > 0 {
+		neighbors = append(neighbors, lineNode{l.position - 1, l.length})
+	}
+	if l.position < l.length-1 {
+		neighbors = append(neighbors, lineNode{l.position + 1, l.length})
+	}
+	return neighbors
+}
+
+func (l lineNode) Cost() int {
+	if l.position == l.length-1 {
+		return 1
+	}
+	return 10
+}
+
+func distance(from, to lineNode) int {
+	d := from.position - to.position
+	if d < 0 {
+		d = -d
+	}
+	return d
+}
+
+func TestCheck(t *testing.T) {
+	root := lineNode{position: 0, length: 4}
+
+	t.Run("admissible", func(t *testing.T) {
+		Heuristic(t, distance, root)
+	})
+
+	t.Run("overestimate", func(t *testing.T) {
+		scaled := func(from, to lineNode) int { return distance(from, to) * 10 }
+		report := Check(scaled, root)
+		if report.OK() {
+			t.Fatal("Expected violations")
+		}
+
+		// only paths which end at the cheaper block are overestimated
+		for _, o := range report.Overestimates {
+			if o.To.position != 3 {
+				t.Fatalf("Unexpected overestimate: %+v", o)
+			}
+		}
+		if len(report.Overestimates) != 3 {
+			t.Fatalf("Expected 3 overestimates, got %d", len(report.Overestimates))
+		}
+		// the only inconsistent edge leads into the cheaper block
+		for _, i := range report.Inconsistencies {
+			if i.From.position != 2 || i.Next.position != 3 {
+				t.Fatalf("Unexpected inconsistency: %+v", i)
+			}
+		}
+		if len(report.Inconsistencies) == 0 {
+			t.Fatal("Expected inconsistencies")
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		// nodes on lines of different lengths are never connected, so no estimate
+		// between them is an overestimate
+		heuristic := func(from, to lineNode) int {
+			if from.length != to.length {
+				return 100
+			}
+			return 0
+		}
+		report := Check(heuristic, lineNode{0, 1}, lineNode{0, 2})
+		if !report.OK() {
+			t.Fatalf("Unexpected violations: %+v", report)
+		}
+	})
+}
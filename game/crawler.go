@@ -0,0 +1,57 @@
+package game
+
+import (
+	"time"
+
+	"github.com/agstrc/heuristic-search/game/mission"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// stepInterval defines the waiting time between the agent's position updates.
+const stepInterval = time.Millisecond * 100
+
+// crawler defines the game's state while the agent carries out its mission, which is
+// collecting all of the dungeons' goals, heading back to the starting point and then to
+// the Lost Woods' gate. The agent walks through the game's world, so entering and
+// leaving a dungeon are ordinary steps.
+type crawler struct {
+	game  *Game
+	agent *mission.Agent
+
+	// lastStep is the moment when the agent took its last step.
+	lastStep time.Time
+}
+
+// newCrawler returns a crawler whose agent carries out the mission of the game's plan.
+func newCrawler(game *Game) *crawler {
+	return &crawler{game: game, agent: mission.NewAgent(mission.New(game.plan))}
+}
+
+func (c *crawler) update() error {
+	if time.Since(c.lastStep) < stepInterval {
+		return nil
+	}
+
+	if c.agent.Step() {
+		c.game.cost = c.agent.Cost()
+		c.lastStep = time.Now()
+	}
+	return nil
+}
+
+func (c *crawler) draw(screen *ebiten.Image) {
+	gamePlan, at := c.game.plan, c.agent.Location()
+	if at.Level == mission.MainLevel {
+		drawPlan(screen, c.game.tiles, gamePlan)
+		drawAgent(screen, at.XY, centerOpts(screen, gamePlan.Grid))
+		return
+	}
+
+	for idx, dungeon := range gamePlan.Dungeons {
+		if mission.DungeonLevel(idx) != at.Level {
+			continue
+		}
+		drawDungeon(screen, dungeon, idx, !c.agent.Collected(idx), c.agent.Keys(idx))
+		drawAgent(screen, at.XY, centerOpts(screen, dungeon.Grid))
+	}
+}
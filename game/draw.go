@@ -10,46 +10,103 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-func drawPlan(screen *ebiten.Image, gamePlan *plan.Plan) {
+func drawPlan(screen *ebiten.Image, tiles tileset, gamePlan *plan.Plan) {
 	grid := gamePlan.Grid
+	opts := centerOpts(screen, grid)
 
-	drawMainGrid(screen, grid)
-	drawDungeons(screen, gamePlan.Dungeons[:])
-	drawImageAt(screen, images.MasterSword, gamePlan.Sword)
-	drawImageAt(screen, images.TransparentDungeon, gamePlan.Gate)
-
+	drawGrid(screen, grid, func(terrain plan.Terrain) *ebiten.Image {
+		return tiles[terrain]
+	}, opts)
+	for _, dungeon := range gamePlan.Dungeons {
+		drawImageAt(screen, images.Dungeon, dungeon.Entrance, opts)
+	}
+	for _, teleporter := range gamePlan.Teleporters {
+		drawImageAt(screen, teleporterImage(false), teleporter.From, opts)
+		drawImageAt(screen, teleporterImage(teleporter.OneWay), teleporter.To, opts)
+	}
+	drawImageAt(screen, images.MasterSword, gamePlan.Sword, opts)
+	drawImageAt(screen, images.TransparentDungeon, gamePlan.Gate, opts)
 }
 
-func drawImageAt(screen, image *ebiten.Image, at xy.XY) {
+// drawImageAt draws image on the block at "at", after applying opts' transformations.
+func drawImageAt(screen, image *ebiten.Image, at xy.XY, opts ebiten.DrawImageOptions) {
 	x, y := at.X*plan.TIS, at.Y*plan.TIS
-	drawOpts := ebiten.DrawImageOptions{}
-	drawOpts.GeoM.Translate(float64(x), float64(y))
+	opts.GeoM.Translate(float64(x), float64(y))
+
+	screen.DrawImage(image, &opts)
+}
 
-	screen.DrawImage(image, &drawOpts)
+// drawGrid draws every block of grid with the image returned by tile, after applying
+// opts' transformations.
+func drawGrid[T any](
+	screen *ebiten.Image, grid [][]T, tile func(T) *ebiten.Image, opts ebiten.DrawImageOptions,
+) {
+	for y := range grid {
+		for x := range grid[y] {
+			drawImageAt(screen, tile(grid[y][x]), xy.XY{X: x, Y: y}, opts)
+		}
+	}
 }
 
-func drawDungeons(screen *ebiten.Image, dungeons []plan.Dungeon) {
-	for _, dungeon := range dungeons {
-		x, y := dungeon.Entrance.X*plan.TIS, dungeon.Entrance.Y*plan.TIS
-		drawOpts := ebiten.DrawImageOptions{}
-		drawOpts.GeoM.Translate(float64(x), float64(y))
+// centerOpts returns options with a translation that places grid at the center of the
+// screen.
+func centerOpts[T any](screen *ebiten.Image, grid [][]T) ebiten.DrawImageOptions {
+	screenWidth, screenHeight := screen.Size()
+	gridWidth, gridHeight := len(grid[0])*plan.TIS, len(grid)*plan.TIS
 
-		screen.DrawImage(images.Dungeon, &drawOpts)
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(
+		float64((screenWidth-gridWidth)/2), float64((screenHeight-gridHeight)/2),
+	)
+	return opts
+}
+
+// screenSize returns the size of a screen on which every map of the plan fits.
+func screenSize(gamePlan *plan.Plan) (width, height int) {
+	width, height = len(gamePlan.Grid[0]), len(gamePlan.Grid)
+	for _, dungeon := range gamePlan.Dungeons {
+		if len(dungeon.Grid[0]) > width {
+			width = len(dungeon.Grid[0])
+		}
+		if len(dungeon.Grid) > height {
+			height = len(dungeon.Grid)
+		}
 	}
+	return width * plan.TIS, height * plan.TIS
 }
 
-func drawMainGrid(screen *ebiten.Image, grid [][]plan.Terrain) {
-	for y := range grid {
-		for x := range grid {
-			drawX, drawY := x*plan.TIS, y*plan.TIS
-			drawOpts := ebiten.DrawImageOptions{}
-			drawOpts.GeoM.Translate(float64(drawX), float64(drawY))
+// drawDungeon draws the blocks and the start point of the dungeon at index. Its goal is
+// also drawn if drawGoal is true. Keys which are already held are drawn as floors.
+func drawDungeon(
+	screen *ebiten.Image, dungeon plan.Dungeon, index int, drawGoal bool, held plan.Keys,
+) {
+	opts := centerOpts(screen, dungeon.Grid)
 
-			screen.DrawImage(grid[y][x].Image(), &drawOpts)
+	drawGrid(screen, dungeon.Grid, func(terrain plan.DungeonTerrain) *ebiten.Image {
+		if key, isKey := terrain.Key(); isKey && held.Has(key) {
+			return dungeonTerrainImage(plan.Traversable)
 		}
+		return dungeonTerrainImage(terrain)
+	}, opts)
+	drawImageAt(screen, images.Dungeon, dungeon.Start, opts)
+	if drawGoal {
+		drawImageAt(screen, virtueImage(index), dungeon.GoalXY, opts)
 	}
 }
 
+// drawAgent draws the agent at "at", after applying opts' transformations.
+func drawAgent(screen *ebiten.Image, at xy.XY, opts ebiten.DrawImageOptions) {
+	agentX, agentY := at.X*plan.TIS, at.Y*plan.TIS
+
+	// the agent is taller than a single block. Therefore, he is offset in order to have
+	// its feet at the start of the correct block.
+	agent := images.Agent
+	_, agentHeight := agent.Size()
+	agentY = agentY - (agentHeight - plan.TIS)
+	opts.GeoM.Translate(float64(agentX), float64(agentY))
+	screen.DrawImage(agent, &opts)
+}
+
 var whiteBlock = func() *ebiten.Image {
 	image := ebiten.NewImage(32, 32)
 	image.Fill(color.White)
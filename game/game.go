@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"time"
 
+	"github.com/agstrc/heuristic-search/game/mission"
 	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/game/plan/generate"
+	"github.com/agstrc/heuristic-search/game/plan/picture"
+	"github.com/agstrc/heuristic-search/game/plan/tiled"
 	"github.com/agstrc/heuristic-search/xy"
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -17,8 +20,12 @@ type Game struct {
 	u func() error
 	d func(screen *ebiten.Image)
 
-	plan *plan.Plan
-	cost int
+	plan  *plan.Plan
+	tiles tileset
+	cost  int
+
+	// width and height are the screen's size.
+	width, height int
 }
 
 var _ ebiten.Game = &Game{}
@@ -33,38 +40,113 @@ func (game *Game) Draw(screen *ebiten.Image) {
 }
 
 func (game *Game) Layout(_, _ int) (screenWidth, screenHeight int) {
-	const size = plan.MainMapSize * plan.TIS
-	return size, size
+	return game.width, game.height
 }
 
 // DefaultGame returns a default value of Game.
 func DefaultGame() *Game {
-	// defaultJSONPlan must be valid
-	jplan := plan.DefaultJSONPlan()
-	plan := jplan.ToPlan()
-	game := Game{plan: &plan, cost: 0}
+	// defaultJSONPlan must be valid and only uses predefined terrains
+	game, err := newGame(plan.DefaultJSONPlan().ToPlan())
+	if err != nil {
+		panic(fmt.Sprintf("failed to create default game: %s", err.Error()))
+	}
+	return game
+}
 
-	mapCrawler := mapCrawler{
-		game: &game, agent: jplan.Start,
-		paths: nil, visited: make(map[xy.XY]struct{}),
-		lastStep: time.Time{},
+// GameFromJSON instantiates a new game by settings its map according to a well formatted
+// JSON file, which is migrated from older versions of the format.
+func GameFromJSON(path string) (*Game, error) {
+	jplan, err := plan.LoadJSON(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JSON file: %w", err)
+	}
+	if err := jplan.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid JSON file: %w", err)
 	}
+	return newGame(jplan.ToPlan())
+}
 
-	game.u = mapCrawler.update
-	game.d = mapCrawler.draw
+// GameFromText instantiates a new game by setting its map according to a file in the
+// plain-text plan format.
+func GameFromText(path string) (*Game, error) {
+	jplan, err := plan.LoadText(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text file: %w", err)
+	}
+	if err := jplan.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid text file: %w", err)
+	}
+	return newGame(jplan.ToPlan())
+}
 
-	return &game
+// GameFromTiled instantiates a new game by setting its map according to a map made with
+// the Tiled editor, in either its JSON or XML format.
+func GameFromTiled(path string) (*Game, error) {
+	gamePlan, err := tiled.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import Tiled map: %w", err)
+	}
+	return newGame(gamePlan)
 }
 
-// GameFromJSON instantiates a new game by settings its map according to a well formatted
-// JSON file.
-func GameFromJSON(path string) (*Game, error) {
+// GameFromImage instantiates a new game by setting its map according to a PNG image,
+// whose colors are those of picture.DefaultPalette.
+func GameFromImage(path string) (*Game, error) {
+	gamePlan, err := picture.Load(path, picture.DefaultPalette())
+	if err != nil {
+		return nil, fmt.Errorf("failed to import image: %w", err)
+	}
+	return newGame(gamePlan)
+}
+
+// GeneratedGame instantiates a new game on an overworld generated from seed.
+func GeneratedGame(seed int64) (*Game, error) {
+	jplan, err := generate.Overworld(seed, generate.DefaultOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate a map: %w", err)
+	}
+	return newGame(jplan.ToPlan())
+}
+
+func newGame(plan plan.Plan) (*Game, error) {
+	// the crawler plans the mission right away, which is only possible on solvable plans
+	if _, err := mission.Analyze(&plan); err != nil {
+		return nil, err
+	}
+	tiles, err := newTileset(plan.Grid)
+	if err != nil {
+		return nil, err
+	}
+
+	game := Game{plan: &plan, tiles: tiles, cost: 0}
+	game.width, game.height = screenSize(&plan)
+
+	crawler := newCrawler(&game)
+	game.u = crawler.update
+	game.d = crawler.draw
+
+	return &game, nil
+}
+
+// DefaultHexGame returns a game played on the default hexagonal map.
+func DefaultHexGame() *Game {
+	// defaultJSONHexPlan must be valid and only uses predefined terrains
+	game, err := newHexGame(plan.DefaultJSONHexPlan())
+	if err != nil {
+		panic(fmt.Sprintf("failed to create default hex game: %s", err.Error()))
+	}
+	return game
+}
+
+// HexGameFromJSON instantiates a new game played on a hexagonal map, which is read from
+// a well formatted JSON file.
+func HexGameFromJSON(path string) (*Game, error) {
 	fileData, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	var jplan plan.JSONPlan
+	var jplan plan.JSONHexPlan
 
 	if err := json.Unmarshal(fileData, &jplan); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal JSON file: %w", err)
@@ -72,17 +154,28 @@ func GameFromJSON(path string) (*Game, error) {
 	if err := jplan.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid JSON file: %w", err)
 	}
-	plan := jplan.ToPlan()
-	game := Game{plan: &plan, cost: 0}
+	return newHexGame(jplan)
+}
 
-	mapCrawler := mapCrawler{
-		game: &game, agent: jplan.Start,
-		paths: nil, visited: make(map[xy.XY]struct{}),
-		lastStep: time.Time{},
+func newHexGame(jplan plan.JSONHexPlan) (*Game, error) {
+	hexPlan := jplan.ToHexPlan()
+	// the crawler walks to the goal right away, which is only possible if it is reachable
+	if !hexPlan.Reachable() {
+		return nil, fmt.Errorf(
+			"%w: (%d, %d)", plan.ErrUnreachable, hexPlan.Start.X, hexPlan.Start.Y,
+		)
 	}
+	tiles, err := newTileset(hexPlan.Grid)
+	if err != nil {
+		return nil, err
+	}
+
+	game := Game{tiles: tiles, cost: 0}
+	game.width, game.height = hexLayout(hexPlan.Grid)
 
-	game.u = mapCrawler.update
-	game.d = mapCrawler.draw
+	crawler := hexCrawler{game: &game, plan: &hexPlan, agent: hexPlan.Start}
+	game.u = crawler.update
+	game.d = crawler.draw
 
 	return &game, nil
 }
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"math"
+	"time"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/images"
+	"github.com/agstrc/heuristic-search/xy"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// hexNode is a node on a position within a hexagonal terrain grid.
+type hexNode = grid.Node[plan.Terrain]
+
+// hexHeuristic implements a heuristic on a pair of hexNodes which may be used on the A*
+// algorithm.
+var hexHeuristic = grid.Hex[plan.Terrain](plan.Grass.Cost())
+
+// hexSize is the distance between the center and the corners of a drawn hexagon. It is
+// chosen so that a hexagon is as wide as a terrain image.
+var hexSize = plan.TIS / math.Sqrt(3)
+
+// hexCrawler defines the game's state while the agent walks from the start to the goal
+// of a hexagonal map.
+type hexCrawler struct {
+	game  *Game
+	plan  *plan.HexPlan
+	agent xy.XY
+	path  []hexNode
+
+	// lastStep is the moment when the agent took its last step.
+	lastStep time.Time
+}
+
+// hexLayout returns the size of the screen required to draw a hexagonal grid.
+func hexLayout(terrains [][]plan.Terrain) (width, height int) {
+	rows := len(terrains)
+	width = int(math.Ceil((float64(len(terrains[0])) + 0.5) * plan.TIS))
+	height = int(math.Ceil(hexSize * (1.5*float64(rows-1) + 2)))
+	return width, height
+}
+
+func (hc *hexCrawler) initPath() {
+	graph := hc.plan.Graph()
+	path, _ := astar.FindPath(graph.Node(hc.plan.Start), graph.Node(hc.plan.Goal), hexHeuristic)
+	if path == nil {
+		// validated plans always have a path, but the agent is left standing otherwise
+		hc.path = []hexNode{}
+		return
+	}
+	// skips the current position (same as start)
+	hc.path = path[1:]
+}
+
+func (hc *hexCrawler) update() error {
+	if hc.path == nil {
+		hc.initPath()
+	}
+	if time.Since(hc.lastStep) < stepInterval || len(hc.path) == 0 {
+		return nil
+	}
+
+	hc.agent = hc.path[0].XY
+	hc.game.cost += hc.path[0].Cost()
+	hc.path = hc.path[1:]
+	hc.lastStep = time.Now()
+
+	return nil
+}
+
+func (hc *hexCrawler) draw(screen *ebiten.Image) {
+	drawHexGrid(screen, hc.game.tiles, hc.plan.Grid)
+	if hc.agent != hc.plan.Goal {
+		drawImageAtHex(screen, images.MasterSword, hc.plan.Goal)
+	}
+
+	// the agent is taller than a single block. Therefore, he is offset in order to have
+	// its feet at the bottom of the correct block.
+	agent := images.Agent
+	_, agentHeight := agent.Size()
+	x, y := hexCenter(hc.agent)
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(x-plan.TIS/2, y+plan.TIS/2-float64(agentHeight))
+	screen.DrawImage(agent, &opts)
+}
+
+// hexCenter returns the screen coordinates of the center of the hexagon at "at".
+func hexCenter(at xy.XY) (float64, float64) {
+	x := plan.TIS * (float64(at.X) + 0.5*float64(at.Y&1) + 0.5)
+	y := hexSize * (1.5*float64(at.Y) + 1)
+	return x, y
+}
+
+// drawHexGrid draws every terrain of a hexagonal grid.
+func drawHexGrid(screen *ebiten.Image, tiles tileset, terrains [][]plan.Terrain) {
+	for y, row := range terrains {
+		for x, terrain := range row {
+			drawHexagon(screen, tiles[terrain], xy.XY{X: x, Y: y})
+		}
+	}
+}
+
+// drawHexagon draws image, clipped to a pointy-top hexagon, on the hexagon at "at".
+func drawHexagon(screen, image *ebiten.Image, at xy.XY) {
+	centerX, centerY := hexCenter(at)
+	imageWidth, imageHeight := image.Size()
+
+	// vertex maps a point of the screen to the matching point of the image, which is
+	// stretched over the hexagon's bounding box
+	vertex := func(x, y float64) ebiten.Vertex {
+		return ebiten.Vertex{
+			DstX: float32(x), DstY: float32(y),
+			SrcX:   float32(((x-centerX)/plan.TIS + 0.5) * float64(imageWidth)),
+			SrcY:   float32(((y-centerY)/(2*hexSize) + 0.5) * float64(imageHeight)),
+			ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1,
+		}
+	}
+
+	vertices := []ebiten.Vertex{vertex(centerX, centerY)}
+	indices := make([]uint16, 0, 18)
+	for corner := 0; corner < 6; corner++ {
+		angle := math.Pi / 180 * float64(60*corner-30)
+		vertices = append(vertices, vertex(
+			centerX+hexSize*math.Cos(angle), centerY+hexSize*math.Sin(angle),
+		))
+		indices = append(indices, 0, uint16(corner+1), uint16((corner+1)%6+1))
+	}
+
+	screen.DrawTriangles(vertices, indices, image, nil)
+}
+
+// drawImageAtHex draws image centered on the hexagon at "at".
+func drawImageAtHex(screen, image *ebiten.Image, at xy.XY) {
+	x, y := hexCenter(at)
+	width, height := image.Size()
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(x-float64(width)/2, y-float64(height)/2)
+	screen.DrawImage(image, &opts)
+}
@@ -0,0 +1,82 @@
+package mission
+
+import (
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/world"
+)
+
+// Agent walks along a mission's path one step at a time, keeping track of the cost of the
+// steps taken so far and of the collected goals.
+type Agent struct {
+	mission   *Mission
+	location  world.Location
+	remaining []world.Node
+	cost      int
+
+	// collected is a set of the indexes of the dungeons whose goals have been collected.
+	collected map[int]struct{}
+	// keys maps the index of each dungeon to the keys picked up within it.
+	keys map[int]plan.Keys
+}
+
+// NewAgent returns an agent which stands at the start of mission.
+func NewAgent(mission *Mission) *Agent {
+	return &Agent{
+		mission: mission, location: mission.Start, remaining: mission.Path,
+		collected: make(map[int]struct{}), keys: make(map[int]plan.Keys),
+	}
+}
+
+// Step moves the agent one step further along the mission's path. It reports whether a
+// step was taken, which is no longer the case once the mission is over.
+func (a *Agent) Step() bool {
+	if len(a.remaining) == 0 {
+		return false
+	}
+
+	next := a.remaining[0]
+	a.cost += stepCost(a.mission.World.Node(a.location), next)
+	a.location = next.Location
+	a.remaining = a.remaining[1:]
+
+	for idx, goal := range a.mission.Goals {
+		if goal == a.location {
+			a.collected[idx] = struct{}{}
+		}
+	}
+	for idx, dungeon := range a.mission.Plan.Dungeons {
+		if DungeonLevel(idx) != a.location.Level {
+			continue
+		}
+		if key, isKey := dungeon.Grid[a.location.Y][a.location.X].Key(); isKey {
+			a.keys[idx] = a.keys[idx].With(key)
+		}
+	}
+	return true
+}
+
+// Done reports whether the agent has reached the end of the mission.
+func (a *Agent) Done() bool {
+	return len(a.remaining) == 0
+}
+
+// Location returns where the agent currently stands.
+func (a *Agent) Location() world.Location {
+	return a.location
+}
+
+// Cost returns the cost of the steps taken so far.
+func (a *Agent) Cost() int {
+	return a.cost
+}
+
+// Collected reports whether the goal of the dungeon at index has been collected.
+func (a *Agent) Collected(index int) bool {
+	_, isCollected := a.collected[index]
+	return isCollected
+}
+
+// Keys returns the keys picked up within the dungeon at index.
+func (a *Agent) Keys(index int) plan.Keys {
+	return a.keys[index]
+}
@@ -0,0 +1,193 @@
+package mission
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/world"
+)
+
+// Objective is a location the agent must reach during its mission.
+type Objective struct {
+	// Name describes the objective, such as "goal of dungeon 1".
+	Name     string
+	Location world.Location
+}
+
+func (o Objective) String() string {
+	return fmt.Sprintf("%s at (%d, %d)", o.Name, o.Location.X, o.Location.Y)
+}
+
+// UnreachableError reports an objective which may not be reached from another one, even
+// though the mission requires walking from one to the other.
+type UnreachableError struct {
+	From, To Objective
+	// Component is the connected component of To within its level's grid, and
+	// Components is the amount of components of that grid.
+	Component, Components int
+}
+
+func (ue *UnreachableError) Error() string {
+	message := fmt.Sprintf("%v may not be reached from %v", ue.To, ue.From)
+	if ue.Components > 1 {
+		message += fmt.Sprintf(
+			": level %q is split into %d separate areas and it lies in area %d",
+			ue.To.Location.Level, ue.Components, ue.Component+1,
+		)
+	}
+	return message
+}
+
+// UnsolvableError lists every reason why a plan's mission may not be carried out. Both
+// errors.Is and errors.As look through every one of them.
+type UnsolvableError []*UnreachableError
+
+func (ue UnsolvableError) Error() string {
+	messages := make([]string, len(ue))
+	for idx, err := range ue {
+		messages[idx] = err.Error()
+	}
+	return "mission is unsolvable: " + strings.Join(messages, "; ")
+}
+
+func (ue UnsolvableError) Is(target error) bool {
+	for _, err := range ue {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ue UnsolvableError) As(target any) bool {
+	for _, err := range ue {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Analysis describes the structure of a plan's world and the cost of its mission.
+type Analysis struct {
+	// Components maps each level to the connected components of its grid, as labeled
+	// by grid.Graph.Components.
+	Components map[string]*grid.Grid[int]
+	// ComponentCounts maps each level to the amount of components of its grid.
+	ComponentCounts map[string]int
+
+	// LowerBound never exceeds the cost of the plan's mission. It is the cost of going to
+	// the farthest dungeon goal and back to the start, followed by the cost of going from
+	// the start to the Lost Woods' gate.
+	LowerBound int
+}
+
+// Analyze checks whether the mission of gamePlan may be carried out, which is the case
+// when every dungeon's goal may be reached from the start and the start may be reached
+// back from each of them, and the Lost Woods' gate may be reached from the start. Within
+// dungeons, the keys which open doors and the direction of ledges are accounted for. If it
+// may not, the returned error is an UnsolvableError.
+//
+// Analyze is much cheaper than planning the mission itself, so it should be called
+// before New, which must only be called on solvable plans.
+func Analyze(gamePlan *plan.Plan) (Analysis, error) {
+	analysis := Analysis{
+		Components:      make(map[string]*grid.Grid[int]),
+		ComponentCounts: make(map[string]int),
+	}
+	labels, count := mainGraph(gamePlan.Grid).Components()
+	analysis.Components[MainLevel], analysis.ComponentCounts[MainLevel] = labels, count
+	for idx, dungeon := range gamePlan.Dungeons {
+		labels, count := dungeonGraph(dungeon.Grid).Components()
+		analysis.Components[DungeonLevel(idx)] = labels
+		analysis.ComponentCounts[DungeonLevel(idx)] = count
+	}
+
+	w := NewWorld(gamePlan)
+	start := Objective{
+		Name: "start", Location: world.Location{Level: MainLevel, XY: gamePlan.Start},
+	}
+	gate := Objective{
+		Name: "Lost Woods' gate", Location: world.Location{Level: MainLevel, XY: gamePlan.Gate},
+	}
+
+	var unsolvable UnsolvableError
+	// cost returns the cost of the cheapest path between two objectives, given the costs
+	// from the first one. An unreachable objective is recorded and its cost is zero.
+	cost := func(costs map[world.Node]int, from, to Objective) int {
+		cost, isReachable := costs[w.Node(to.Location)]
+		if !isReachable {
+			level := to.Location.Level
+			unsolvable = append(unsolvable, &UnreachableError{
+				From: from, To: to,
+				Component:  analysis.Components[level].At(to.Location.XY),
+				Components: analysis.ComponentCounts[level],
+			})
+		}
+		return cost
+	}
+
+	costsFromStart := astar.Costs(w.Node(start.Location))
+	farthest := 0
+	for idx := range gamePlan.Dungeons {
+		dungeon := &gamePlan.Dungeons[idx]
+		entrance := Objective{
+			Name:     fmt.Sprintf("entrance of dungeon %d", idx),
+			Location: world.Location{Level: MainLevel, XY: dungeon.Entrance},
+		}
+		if !dungeonReachable(&unsolvable, analysis, dungeon, idx) {
+			continue
+		}
+
+		there := cost(costsFromStart, start, entrance)
+		if _, isReachable := costsFromStart[w.Node(entrance.Location)]; !isReachable {
+			continue
+		}
+		back := cost(astar.Costs(w.Node(entrance.Location)), entrance, start)
+		if total := there + visit(w, dungeon, idx).cost + back; total > farthest {
+			farthest = total
+		}
+	}
+	analysis.LowerBound = farthest + cost(costsFromStart, start, gate)
+
+	if len(unsolvable) > 0 {
+		return analysis, unsolvable
+	}
+	return analysis, nil
+}
+
+// dungeonReachable reports whether the dungeon at index has a round trip from its start
+// through its goal. If it does not, the reason is recorded in unsolvable.
+func dungeonReachable(
+	unsolvable *UnsolvableError, analysis Analysis, dungeon *plan.Dungeon, index int,
+) bool {
+	level := DungeonLevel(index)
+	start := Objective{
+		Name:     fmt.Sprintf("start of dungeon %d", index),
+		Location: world.Location{Level: level, XY: dungeon.Start},
+	}
+	goal := Objective{
+		Name:     fmt.Sprintf("goal of dungeon %d", index),
+		Location: world.Location{Level: level, XY: dungeon.GoalXY},
+	}
+
+	from, to := start, goal
+	switch reachesGoal, reachesBack := dungeon.Reachable(); {
+	case !reachesGoal:
+		// the goal is unreachable from the start, as set above
+	case !reachesBack:
+		from, to = goal, start
+	default:
+		return true
+	}
+	*unsolvable = append(*unsolvable, &UnreachableError{
+		From: from, To: to,
+		Component:  analysis.Components[level].At(to.Location.XY),
+		Components: analysis.ComponentCounts[level],
+	})
+	return false
+}
@@ -0,0 +1,180 @@
+// Package mission plans and simulates the agent's mission on a plan, which is collecting
+// the goals of all dungeons, heading back to the starting point and then to the Lost
+// Woods' gate. It does not depend on ebiten, so missions may be planned without a
+// graphics context.
+package mission
+
+import (
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/world"
+)
+
+// Mission is the best route to carry out the mission of a plan.
+type Mission struct {
+	// Plan is the plan whose mission is carried out.
+	Plan *plan.Plan
+	// World is the world formed by the plan, which the mission's path walks through.
+	World *world.World
+	// Start is where the mission starts and Gate is where it ends.
+	Start world.Location
+	Gate  world.Location
+	// Goals are the locations of the dungeons' goals, indexed by dungeon.
+	Goals []world.Location
+
+	// Order is the order in which the dungeons are visited.
+	Order []int
+	// Path is the mission's path, which does not include Start, and Cost is its cost.
+	// Stepping into or out of a dungeon is free, so Cost is the sum of the costs of the
+	// blocks walked on within each level.
+	Path []world.Node
+	Cost int
+}
+
+// New plans the mission of gamePlan. Every possible order of visiting the dungeons is
+// considered, and the cheapest one is kept. The plan's mission must be solvable, which
+// is checked by Analyze.
+func New(gamePlan *plan.Plan) *Mission {
+	mission := Mission{
+		Plan:  gamePlan,
+		World: NewWorld(gamePlan),
+		Start: world.Location{Level: MainLevel, XY: gamePlan.Start},
+		Gate:  world.Location{Level: MainLevel, XY: gamePlan.Gate},
+	}
+
+	order := make([]int, len(gamePlan.Dungeons))
+	entrances := make([]world.Location, len(gamePlan.Dungeons))
+	// a dungeon may only be entered and left through its entrance, so the visit of a
+	// dungeon is the same whatever the visiting order
+	visits := make(map[world.Location]leg)
+	for idx, dungeon := range gamePlan.Dungeons {
+		order[idx] = idx
+		mission.Goals = append(mission.Goals, world.Location{
+			Level: DungeonLevel(idx), XY: dungeon.GoalXY,
+		})
+		entrances[idx] = world.Location{Level: MainLevel, XY: dungeon.Entrance}
+		visits[entrances[idx]] = visit(mission.World, &gamePlan.Dungeons[idx], idx)
+	}
+	// every visiting order goes through the same legs, so they are only searched once
+	legs := make(map[[2]world.Location]leg)
+
+	for _, order := range permutations(order) {
+		var objs []world.Location
+		for _, index := range order {
+			objs = append(objs, entrances[index])
+		}
+		objs = append(objs, mission.Start, mission.Gate)
+
+		path, cost := multiPath(mission.World, legs, visits, mission.Start, objs...)
+		if mission.Path == nil || cost < mission.Cost {
+			mission.Order, mission.Path, mission.Cost = order, path, cost
+		}
+	}
+
+	return &mission
+}
+
+// leg is the path between two consecutive objectives, which does not include the
+// first one, along with its cost.
+type leg struct {
+	path []world.Node
+	cost int
+}
+
+// visit returns the path which enters the dungeon at index from its entrance, makes the
+// cheapest round trip through it and leaves it back to the entrance. The round trip is
+// searched on the dungeon's state space, as doors depend on the keys held. Entering and
+// leaving the dungeon are free, so the visit costs as much as its round trip.
+func visit(w *world.World, dungeon *plan.Dungeon, index int) leg {
+	trip, _ := dungeon.RoundTrip()
+	entrance := w.Node(world.Location{Level: MainLevel, XY: dungeon.Entrance})
+
+	var l leg
+	previous := entrance
+	for _, state := range trip {
+		node := w.Node(world.Location{Level: DungeonLevel(index), XY: state.XY})
+		l.path = append(l.path, node)
+		l.cost += stepCost(previous, node)
+		previous = node
+	}
+	l.path = append(l.path, entrance)
+	l.cost += stepCost(previous, entrance)
+	return l
+}
+
+// stepCost returns the cost of the mission's step from one node into the next. Steps
+// between levels, which go through a dungeon's entrance, are free: the agent is charged
+// for the blocks it walks on, not for being moved between the main map and a dungeon.
+// Any other step, including a teleporter's, costs as much as the world says.
+func stepCost(from, next world.Node) int {
+	if from.Level != next.Level {
+		return 0
+	}
+	return from.StepCost(next)
+}
+
+// multiPath calculates the path starting at start and moving through objs in order.
+// Whenever an objective is a dungeon's entrance, its visit, as found in visits, follows.
+// The returned values indicate the path, which does not include start, plus its total
+// cost. Legs are looked up in and added to legs.
+func multiPath(
+	w *world.World, legs map[[2]world.Location]leg, visits map[world.Location]leg,
+	start world.Location, objs ...world.Location,
+) ([]world.Node, int) {
+	ps := []world.Node{}
+	totalCost := 0
+
+	from := start
+	for _, obj := range objs {
+		key := [2]world.Location{from, obj}
+		l, isCached := legs[key]
+		if !isCached {
+			path, cost := astar.FindPath(w.Node(from), w.Node(obj), w.Heuristic)
+			path = path[1:] // skips the current position (same as from)
+			l = leg{path: path, cost: cost}
+			legs[key] = l
+		}
+
+		totalCost += l.cost
+		from = obj
+		ps = append(ps, l.path...)
+		if v, isEntrance := visits[obj]; isEntrance {
+			totalCost += v.cost
+			ps = append(ps, v.path...)
+		}
+	}
+	return ps, totalCost
+}
+
+// permutations returns all permutations of slice.
+func permutations(slice []int) [][]int {
+	if len(slice) == 0 {
+		return [][]int{{}}
+	}
+
+	var helper func([]int, int)
+	res := [][]int{}
+
+	helper = func(arr []int, n int) {
+		if n == 1 {
+			tmp := make([]int, len(arr))
+			copy(tmp, arr)
+			res = append(res, tmp)
+		} else {
+			for i := 0; i < n; i++ {
+				helper(arr, n-1)
+				if n%2 == 1 {
+					tmp := arr[i]
+					arr[i] = arr[n-1]
+					arr[n-1] = tmp
+				} else {
+					tmp := arr[0]
+					arr[0] = arr[n-1]
+					arr[n-1] = tmp
+				}
+			}
+		}
+	}
+	helper(slice, len(slice))
+	return res
+}
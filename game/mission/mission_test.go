@@ -0,0 +1,243 @@
+package mission
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/astar/verify"
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/world"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestDefaultMission(t *testing.T) {
+	gamePlan := plan.DefaultJSONPlan().ToPlan()
+	mission := New(&gamePlan)
+	if len(mission.Order) != len(gamePlan.Dungeons) {
+		t.Fatalf("Expected %d dungeons in the order, got %d", len(gamePlan.Dungeons), len(mission.Order))
+	}
+
+	// the cost of the blocks walked on, with free steps into and out of the dungeons
+	if mission.Cost != 6430 {
+		t.Errorf("Expected the default mission to cost 6430, got %d", mission.Cost)
+	}
+
+	agent := NewAgent(mission)
+	for agent.Step() {
+	}
+	if agent.Cost() != mission.Cost {
+		t.Errorf("Agent spent %d, mission costs %d", agent.Cost(), mission.Cost)
+	}
+	if agent.Location() != mission.Gate {
+		t.Errorf("Agent stopped at %v instead of the gate", agent.Location())
+	}
+	for idx := range gamePlan.Dungeons {
+		if !agent.Collected(idx) {
+			t.Errorf("Goal of dungeon %d was not collected", idx)
+		}
+	}
+}
+
+func TestMissionWithoutDungeons(t *testing.T) {
+	jplan := plan.JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 3, Y: 0},
+		Start:       xy.XY{X: 0, Y: 1},
+		MainMap:     []string{"@   ", "  % "},
+	}
+	if err := jplan.Validate(); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	gamePlan := jplan.ToPlan()
+
+	mission := New(&gamePlan)
+	// the cheapest path walks through grass, never through the forest or the mountain
+	if mission.Cost != 40 || len(mission.Path) != 4 {
+		t.Errorf("Expected a path of 4 steps costing 40, got %d steps costing %d",
+			len(mission.Path), mission.Cost)
+	}
+}
+
+func TestHeuristics(t *testing.T) {
+	jplan := plan.JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 1, Y: 0},
+		Start:       xy.XY{X: 4, Y: 1},
+		MainMap:     []string{"@  _*", "  %  ", "*__ @"},
+		Dungeons: []plan.JSONDungeon{{
+			Grid:     []string{"   ", "## ", "   "},
+			Entrance: xy.XY{X: 2, Y: 2},
+			Start:    xy.XY{X: 0, Y: 0},
+			Goal:     xy.XY{X: 0, Y: 2},
+		}},
+	}
+	if err := jplan.Validate(); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	gamePlan := jplan.ToPlan()
+
+	w := NewWorld(&gamePlan)
+	verify.Heuristic(t, w.Heuristic, w.Node(world.Location{Level: MainLevel, XY: gamePlan.Start}))
+}
+
+func TestAnalyze(t *testing.T) {
+	gamePlan := plan.DefaultJSONPlan().ToPlan()
+	analysis, err := Analyze(&gamePlan)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if cost := New(&gamePlan).Cost; analysis.LowerBound <= 0 || analysis.LowerBound > cost {
+		t.Errorf("Lower bound %d is not within (0, %d]", analysis.LowerBound, cost)
+	}
+	if analysis.ComponentCounts[MainLevel] != 1 {
+		t.Errorf("Expected a single main map component, got %d", analysis.ComponentCounts[MainLevel])
+	}
+}
+
+func TestAnalyzeUnsolvable(t *testing.T) {
+	impassable := false
+	jplan := plan.JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 4, Y: 0},
+		Start:       xy.XY{X: 0, Y: 1},
+		Palette: []plan.JSONTerrain{
+			{Char: "^", Name: "cliff", Passable: &impassable, Color: "#5a4a3a"},
+		},
+		MainMap: []string{"  ^  ", "  ^  "},
+		Dungeons: []plan.JSONDungeon{{
+			Grid:     []string{"  "},
+			Entrance: xy.XY{X: 1, Y: 0},
+			Start:    xy.XY{X: 0, Y: 0},
+			Goal:     xy.XY{X: 1, Y: 0},
+		}},
+	}
+	if err := jplan.Validate(); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	gamePlan := jplan.ToPlan()
+
+	analysis, err := Analyze(&gamePlan)
+	var unreachable *UnreachableError
+	if !errors.As(err, &unreachable) {
+		t.Fatalf("Expected an unreachable objective, got %v", err)
+	}
+	if unreachable.To.Location.XY != gamePlan.Gate || unreachable.Component != 1 {
+		t.Errorf("Unexpected unreachable objective: %v", unreachable)
+	}
+	if analysis.ComponentCounts[MainLevel] != 2 {
+		t.Errorf("Expected 2 main map components, got %d", analysis.ComponentCounts[MainLevel])
+	}
+}
+
+func TestMissionWithKeys(t *testing.T) {
+	jplan := plan.JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 3, Y: 0},
+		Start:       xy.XY{X: 0, Y: 1},
+		MainMap:     []string{"    ", "    "},
+		Dungeons: []plan.JSONDungeon{{
+			// the key lies behind the start and the way back avoids the trap
+			Grid:     []string{"   A  ", "a#!#> ", "  #   "},
+			Entrance: xy.XY{X: 2, Y: 1},
+			Start:    xy.XY{X: 0, Y: 0},
+			Goal:     xy.XY{X: 5, Y: 1},
+		}},
+	}
+	if err := jplan.Validate(); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	gamePlan := jplan.ToPlan()
+	if _, err := Analyze(&gamePlan); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	mission := New(&gamePlan)
+	agent := NewAgent(mission)
+	for agent.Step() {
+	}
+	if agent.Cost() != mission.Cost {
+		t.Errorf("Agent spent %d, mission costs %d", agent.Cost(), mission.Cost)
+	}
+	if !agent.Collected(0) || !agent.Keys(0).Has(0) {
+		t.Error("Expected the goal and the key to be collected")
+	}
+}
+
+func TestAnalyzeOneWayDungeon(t *testing.T) {
+	jplan := plan.JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 3, Y: 0},
+		Start:       xy.XY{X: 0, Y: 1},
+		MainMap:     []string{"    ", "    "},
+		Dungeons: []plan.JSONDungeon{{
+			Grid:     []string{" v ", "#  "},
+			Entrance: xy.XY{X: 2, Y: 1},
+			Start:    xy.XY{X: 1, Y: 0},
+			Goal:     xy.XY{X: 1, Y: 1},
+		}},
+	}
+	gamePlan := jplan.ToPlan()
+
+	_, err := Analyze(&gamePlan)
+	var unreachable *UnreachableError
+	if !errors.As(err, &unreachable) {
+		t.Fatalf("Expected an unreachable objective, got %v", err)
+	}
+	if unreachable.From.Location.XY != gamePlan.Dungeons[0].GoalXY ||
+		unreachable.To.Location.XY != gamePlan.Dungeons[0].Start {
+		t.Errorf("Expected the way back from the goal to be reported, got %v", unreachable)
+	}
+}
+
+func TestMissionWithTeleporters(t *testing.T) {
+	jplan := plan.JSONPlan{
+		MasterSword: xy.XY{X: 3, Y: 0},
+		Palette: []plan.JSONTerrain{
+			{Char: "v", Name: "waterfall", Cost: 30, Direction: "south", Color: "#5080e0"},
+		},
+		// the mountains may only be crossed through the warp or down the waterfall
+		MainMap: []string{"      ", "%%%%%v", "      "},
+		Teleporters: []plan.JSONTeleporter{
+			{From: xy.XY{X: 1, Y: 2}, To: xy.XY{X: 1, Y: 0}, OneWay: true},
+		},
+	}
+	tests := []struct {
+		name        string
+		start, gate xy.XY
+		cost        int
+	}{
+		{"warp", xy.XY{X: 0, Y: 2}, xy.XY{X: 0, Y: 0}, 30},
+		{"waterfall", xy.XY{X: 0, Y: 0}, xy.XY{X: 0, Y: 2}, 140},
+	}
+
+	for _, test := range tests {
+		jplan.Start, jplan.LostWoods = test.start, test.gate
+		if err := jplan.Validate(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		gamePlan := jplan.ToPlan()
+
+		mission := New(&gamePlan)
+		if mission.Cost != test.cost {
+			t.Errorf("%s: expected a mission costing %d, got %d", test.name, test.cost, mission.Cost)
+		}
+		agent := NewAgent(mission)
+		for agent.Step() {
+		}
+		if agent.Cost() != mission.Cost {
+			t.Errorf("%s: agent spent %d, mission costs %d", test.name, agent.Cost(), mission.Cost)
+		}
+
+		// the main map is the only level, so the path may be validated on it
+		path := []xy.XY{mission.Start.XY}
+		for _, node := range mission.Path {
+			path = append(path, node.XY)
+		}
+		if err := gamePlan.ValidatePath(path, mission.Cost); err != nil {
+			t.Errorf("%s: invalid mission path: %v", test.name, err)
+		}
+
+		w := mission.World
+		verify.Heuristic(t, w.Heuristic, w.Node(mission.Start), w.Node(mission.Gate))
+	}
+}
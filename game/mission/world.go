@@ -0,0 +1,89 @@
+package mission
+
+import (
+	"fmt"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/world"
+)
+
+// This file builds the graphs which the agent's paths are searched on.
+
+// mainGraph returns the graph formed by the main map's grid. Non traversable terrains,
+// which may only be declared by a palette, are not connected to any nodes, and one-way
+// terrains are only connected to the nodes they may be entered from.
+func mainGraph(terrains [][]plan.Terrain) *grid.Graph[plan.Terrain] {
+	return &grid.Graph[plan.Terrain]{
+		Grid:      grid.FromRows(terrains),
+		Passable:  plan.Terrain.Traversable,
+		Cost:      plan.Terrain.Cost,
+		Enterable: plan.Terrain.Enterable,
+	}
+}
+
+// dungeonGraph returns the graph formed by a dungeon's grid. Non traversable terrains
+// are not connected to any nodes. Doors and ledges are connected regardless of the keys
+// held or of the direction of a step, so paths within dungeons are searched on their
+// state space instead, through plan.Dungeon.RoundTrip.
+func dungeonGraph(terrains [][]plan.DungeonTerrain) *grid.Graph[plan.DungeonTerrain] {
+	return &grid.Graph[plan.DungeonTerrain]{
+		Grid:     grid.FromRows(terrains),
+		Passable: plan.DungeonTerrain.Traversable,
+		Cost:     plan.DungeonTerrain.Cost,
+	}
+}
+
+// tHeuristic returns a heuristic on a pair of main map nodes which may be used on the A*
+// algorithm. The Manhattan distance is scaled by the cost of the cheapest terrain of
+// terrains, so the heuristic never overestimates the cost of a path. One-way terrains
+// only make paths longer, and the world's heuristic accounts for teleporters.
+func tHeuristic(terrains [][]plan.Terrain) astar.Heuristic[grid.Node[plan.Terrain]] {
+	return grid.Manhattan[plan.Terrain](plan.MinCost(terrains))
+}
+
+// dtHeuristic implements a heuristic on a pair of dungeon nodes which may be used on the
+// A* algorithm. As no traversable dungeon terrain is cheaper than a floor, the Manhattan
+// distance is scaled by the cost of a floor.
+var dtHeuristic = grid.Manhattan[plan.DungeonTerrain](plan.Traversable.Cost())
+
+// MainLevel is the name of the main map's level within the game's world.
+const MainLevel = "main"
+
+// DungeonLevel returns the name of the dungeon's level within the game's world.
+func DungeonLevel(index int) string {
+	return fmt.Sprintf("dungeon %d", index)
+}
+
+// NewWorld returns the world formed by the plan's main map and dungeons. Each dungeon's
+// entrance is connected to its start, so entering and leaving a dungeon are ordinary
+// steps of a path, although missions do not charge them. See dungeonGraph for the
+// limits of the dungeons' levels. Teleporters are portals within the main map, so
+// taking one is an ordinary step as well.
+func NewWorld(gamePlan *plan.Plan) *world.World {
+	var w world.World
+	w.AddLevel(world.NewLevel(
+		MainLevel, mainGraph(gamePlan.Grid), tHeuristic(gamePlan.Grid),
+	))
+
+	for idx, dungeon := range gamePlan.Dungeons {
+		name := DungeonLevel(idx)
+		w.AddLevel(world.NewLevel(name, dungeonGraph(dungeon.Grid), dtHeuristic))
+		w.Connect(
+			world.Location{Level: MainLevel, XY: dungeon.Entrance},
+			world.Location{Level: name, XY: dungeon.Start},
+		)
+	}
+	for _, teleporter := range gamePlan.Teleporters {
+		from := world.Location{Level: MainLevel, XY: teleporter.From}
+		to := world.Location{Level: MainLevel, XY: teleporter.To}
+		if teleporter.OneWay {
+			w.Link(from, to)
+		} else {
+			w.Connect(from, to)
+		}
+	}
+
+	return &w
+}
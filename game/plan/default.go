@@ -9,10 +9,12 @@ import (
 //go:embed default_plan.json
 var defaultJSONPlanData []byte
 
-func DefaultJSONPlan() JSONPlan {
-	var jplan JSONPlan
+//go:embed default_hex_plan.json
+var defaultJSONHexPlanData []byte
 
-	if err := json.Unmarshal(defaultJSONPlanData, &jplan); err != nil {
+func DefaultJSONPlan() JSONPlan {
+	jplan, err := ParseJSON(defaultJSONPlanData)
+	if err != nil {
 		errorMessage := fmt.Sprintf("failed to unmarshal default JSONPlan: %s", err.Error())
 		panic(errorMessage)
 	}
@@ -22,3 +24,17 @@ func DefaultJSONPlan() JSONPlan {
 	}
 	return jplan
 }
+
+func DefaultJSONHexPlan() JSONHexPlan {
+	var jplan JSONHexPlan
+
+	if err := json.Unmarshal(defaultJSONHexPlanData, &jplan); err != nil {
+		errorMessage := fmt.Sprintf("failed to unmarshal default JSONHexPlan: %s", err.Error())
+		panic(errorMessage)
+	}
+	if err := jplan.Validate(); err != nil {
+		errorMessage := fmt.Sprintf("default JSONHexPlan is invalid: %s", err.Error())
+		panic(errorMessage)
+	}
+	return jplan
+}
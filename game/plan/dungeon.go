@@ -0,0 +1,117 @@
+package plan
+
+import (
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// Keys is a set of the keys held within a dungeon, whose bits are indexed by key.
+type Keys uint16
+
+// Has reports whether the key at index is held.
+func (k Keys) Has(index int) bool {
+	return k&(1<<index) != 0
+}
+
+// With returns the set along with the key at index.
+func (k Keys) With(index int) Keys {
+	return k | 1<<index
+}
+
+// DungeonState is a state of the agent's round trip through a dungeon, which starts at
+// the dungeon's start, goes through its goal and ends back at the start. It implements
+// astar.Node, so round trips are searched on the dungeon's state space rather than on
+// its grid: moving through a door depends on the keys held, and the way back from the
+// goal may differ from the way there because of ledges.
+//
+// States are only comparable to states of the same dungeon.
+type DungeonState struct {
+	xy.XY
+	// Keys are the keys picked up so far.
+	Keys Keys
+	// Visited reports whether the goal has been reached.
+	Visited bool
+
+	// dungeon uses a pointer in order to make this struct comparable.
+	dungeon *Dungeon
+}
+
+// State returns the state at the given position of the dungeon, once the key found
+// there, if any, is picked up. Visited is set if the position is the goal. Once back at
+// the start after visiting the goal, the keys are dropped, as the round trip is over.
+func (d *Dungeon) State(at xy.XY, keys Keys, visited bool) DungeonState {
+	if key, isKey := d.Grid[at.Y][at.X].Key(); isKey {
+		keys = keys.With(key)
+	}
+	visited = visited || at == d.GoalXY
+	if visited && at == d.Start {
+		keys = 0
+	}
+	return DungeonState{XY: at, Keys: keys, Visited: visited, dungeon: d}
+}
+
+// RoundTrip returns the cheapest round trip through the dungeon along with its cost. The
+// returned path starts and ends at the dungeon's start. If there is no round trip, such
+// as when a door's key may not be reached, a nil slice is returned.
+func (d *Dungeon) RoundTrip() ([]DungeonState, int) {
+	start, end := d.State(d.Start, 0, false), d.State(d.Start, 0, true)
+	return astar.FindPath(start, end, roundTripHeuristic)
+}
+
+// Reachable reports whether the goal may be reached from the start and whether, once
+// there, the start may be reached back. The dungeon has a round trip only if both are
+// true.
+func (d *Dungeon) Reachable() (goal, back bool) {
+	costs := astar.Costs(d.State(d.Start, 0, false))
+	for state := range costs {
+		goal = goal || state.Visited
+	}
+	_, back = costs[d.State(d.Start, 0, true)]
+	return goal, back
+}
+
+// Neighbors returns the states reached by a single step in any non diagonal direction.
+// A step may not lead onto a wall, onto a door whose key is not held or onto a ledge
+// which points elsewhere. The round trip's last state has no neighbors.
+func (s DungeonState) Neighbors() []DungeonState {
+	d := s.dungeon
+	if s.Visited && s.XY == d.Start {
+		return nil
+	}
+
+	var neighbors []DungeonState
+	for _, at := range s.XY.Neighbors4() {
+		if at.Y < 0 || at.Y >= len(d.Grid) || at.X < 0 || at.X >= len(d.Grid[at.Y]) {
+			continue
+		}
+		terrain := d.Grid[at.Y][at.X]
+		if !terrain.Traversable() {
+			continue
+		}
+		if key, isDoor := terrain.Door(); isDoor && !s.Keys.Has(key) {
+			continue
+		}
+		if direction, isLedge := terrain.Ledge(); isLedge && at.Sub(s.XY) != direction {
+			continue
+		}
+		neighbors = append(neighbors, d.State(at, s.Keys, s.Visited))
+	}
+	return neighbors
+}
+
+// Cost returns the cost to move into the state, which is the cost of its terrain.
+func (s DungeonState) Cost() int {
+	return s.dungeon.Grid[s.Y][s.X].Cost()
+}
+
+// roundTripHeuristic estimates the cost of a path between two states of a round trip,
+// which goes through the goal if it has been visited on the way to the second state
+// only. Every step costs at least as much as a step onto a floor.
+func roundTripHeuristic(from, to DungeonState) int {
+	distance := xy.Manhattan(from.XY, to.XY)
+	if to.Visited && !from.Visited {
+		goal := from.dungeon.GoalXY
+		distance = xy.Manhattan(from.XY, goal) + xy.Manhattan(goal, to.XY)
+	}
+	return distance * Traversable.Cost()
+}
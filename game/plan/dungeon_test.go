@@ -0,0 +1,131 @@
+package plan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/astar/verify"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		grid []string
+		cost int
+	}{
+		// the key lies off the way to the goal, behind the start
+		{"key", []string{"   A  ", "a#####"}, 120},
+		// walking around the trap is cheaper than stepping on it twice
+		{"trap", []string{" ! ", "   "}, 80},
+		// the ledge may only be taken on the way there
+		{"ledge", []string{" > ", "   "}, 60},
+	}
+
+	for _, test := range tests {
+		jdungeon := JSONDungeon{
+			Grid: test.grid, Start: xy.XY{X: 0, Y: 0}, Goal: xy.XY{X: len(test.grid[0]) - 1},
+		}
+		dungeon := jdungeon.toDungeon()
+		path, cost := dungeon.RoundTrip()
+		if path == nil {
+			t.Errorf("%s: no round trip was found", test.name)
+			continue
+		}
+		if cost != test.cost {
+			t.Errorf("%s: expected a round trip costing %d, got %d", test.name, test.cost, cost)
+		}
+		if path[0].XY != dungeon.Start || path[len(path)-1].XY != dungeon.Start {
+			t.Errorf("%s: round trip does not start and end at the start", test.name)
+		}
+		steps := make([]xy.XY, len(path))
+		for idx, state := range path {
+			steps[idx] = state.XY
+		}
+		if err := dungeon.ValidatePath(steps, cost); err != nil {
+			t.Errorf("%s: invalid round trip: %v", test.name, err)
+		}
+		verify.Heuristic(t, roundTripHeuristic, path[0])
+	}
+}
+
+func TestReachable(t *testing.T) {
+	tests := []struct {
+		name       string
+		grid       []string
+		goal, back bool
+	}{
+		{"open", []string{"   "}, true, true},
+		{"locked", []string{" A ", "#a#"}, false, false},
+		{"ledge", []string{" > ", "###"}, true, false},
+	}
+
+	for _, test := range tests {
+		jdungeon := JSONDungeon{Grid: test.grid, Start: xy.XY{X: 0, Y: 0}, Goal: xy.XY{X: 2}}
+		dungeon := jdungeon.toDungeon()
+		if goal, back := dungeon.Reachable(); goal != test.goal || back != test.back {
+			t.Errorf("%s: expected (%t, %t), got (%t, %t)",
+				test.name, test.goal, test.back, goal, back)
+		}
+
+		if _, cost := dungeon.RoundTrip(); !test.back && cost != 0 {
+			t.Errorf("%s: expected no round trip, got one costing %d", test.name, cost)
+		}
+	}
+}
+
+func TestValidateRoundTrip(t *testing.T) {
+	jplan := JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 1, Y: 0},
+		Start:       xy.XY{X: 2, Y: 0},
+		MainMap:     []string{"     "},
+		Dungeons: []JSONDungeon{
+			{Grid: []string{" B b"}, Entrance: xy.XY{X: 3}, Start: xy.XY{}, Goal: xy.XY{X: 2}},
+			{Grid: []string{" > "}, Entrance: xy.XY{X: 4}, Start: xy.XY{}, Goal: xy.XY{X: 2}},
+			{Grid: []string{"   k"}, Entrance: xy.XY{X: 4}, Start: xy.XY{}, Goal: xy.XY{X: 2}},
+		},
+	}
+
+	err := jplan.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	want := []struct {
+		path string
+		kind error
+	}{
+		{"dungeons[0].goal", ErrUnreachable},
+		{"dungeons[1].start", ErrUnreachable},
+		{"dungeons[2].grid[0]", ErrUnknownChar},
+		{"dungeons[2].entrance", ErrOverlap},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d problems, got %d: %v", len(want), len(errs), err)
+	}
+	for idx, problem := range want {
+		if errs[idx].Path != problem.path || !errors.Is(errs[idx], problem.kind) {
+			t.Errorf("Problem %d is %v, expected %v at %s",
+				idx, errs[idx], problem.kind, problem.path)
+		}
+	}
+}
+
+func TestDungeonTerrain(t *testing.T) {
+	if key, isKey := Key(3).Key(); !isKey || key != 3 {
+		t.Errorf("Expected key 3, got %d", key)
+	}
+	if key, isDoor := Door(3).Door(); !isDoor || key != 3 {
+		t.Errorf("Expected a door opened by key 3, got %d", key)
+	}
+	if _, isKey := Door(3).Key(); isKey {
+		t.Error("Expected a door not to hold a key")
+	}
+	if Trap.Cost() <= Traversable.Cost() || NonTraversable.Traversable() {
+		t.Error("Expected traps to be costlier than floors and walls not to be traversable")
+	}
+	if DungeonTerrain('k').Traversable() {
+		t.Error("Expected keys beyond MaxKeys not to be terrains")
+	}
+}
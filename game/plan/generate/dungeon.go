@@ -0,0 +1,314 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// ErrTooShort is returned when no dungeon whose path is long enough was generated.
+var ErrTooShort = errors.New("no path is long enough")
+
+// Layout carves the floor of a dungeon of the given size. The returned cells hold true
+// for floors and false for walls.
+type Layout func(r *rand.Rand, width, height int) [][]bool
+
+// DungeonOptions configures the dungeons created by Dungeon.
+type DungeonOptions struct {
+	Width, Height int
+	// MinPathLength is the least amount of steps of the shortest path from the dungeon's
+	// start to its goal.
+	MinPathLength int
+}
+
+// DefaultDungeonOptions returns the options of dungeons as large as the default plan's.
+func DefaultDungeonOptions() DungeonOptions {
+	return DungeonOptions{Width: 28, Height: 28, MinPathLength: 30}
+}
+
+// Dungeon creates a dungeon whose floor is carved by layout. Only the largest connected
+// area of the floor is kept, and the start and goal are placed on it so that the
+// shortest path between them is at least MinPathLength steps long. The dungeon's
+// entrance is left unset.
+func Dungeon(r *rand.Rand, layout Layout, opts DungeonOptions) (plan.JSONDungeon, error) {
+	if opts.Width < 3 || opts.Height < 3 {
+		return plan.JSONDungeon{}, fmt.Errorf(
+			"invalid dimensions: %dx%d", opts.Width, opts.Height,
+		)
+	}
+
+	// a layout may be too cramped for the path, in which case another one is carved
+	const attempts = 10
+	for attempt := 0; attempt < attempts; attempt++ {
+		graph := &grid.Graph[bool]{
+			Grid:     grid.FromRows(layout(r, opts.Width, opts.Height)),
+			Passable: func(floor bool) bool { return floor },
+			Cost:     func(bool) int { return 1 },
+		}
+		keepLargest(graph.Grid)
+
+		start, goal, ok := placeEnds(r, graph, opts.MinPathLength)
+		if !ok {
+			continue
+		}
+		dungeon := plan.JSONDungeon{Start: start, Goal: goal}
+		for _, row := range graph.Grid.Rows() {
+			var str strings.Builder
+			for _, floor := range row {
+				if floor {
+					str.WriteByte(' ')
+				} else {
+					str.WriteByte('#')
+				}
+			}
+			dungeon.Grid = append(dungeon.Grid, str.String())
+		}
+		return dungeon, nil
+	}
+	return plan.JSONDungeon{}, fmt.Errorf(
+		"%w: %d steps in %d attempts", ErrTooShort, opts.MinPathLength, attempts,
+	)
+}
+
+// RandomDungeon creates a dungeon of the default options, whose layout is picked at
+// random among Rooms, Caves and Maze.
+func RandomDungeon(r *rand.Rand) (plan.JSONDungeon, error) {
+	layouts := [...]Layout{Rooms, Caves, Maze}
+	return Dungeon(r, layouts[r.Intn(len(layouts))], DefaultDungeonOptions())
+}
+
+// keepLargest fills every connected area of the floor with walls, except for the largest
+// one.
+func keepLargest(cells *grid.Grid[bool]) {
+	graph := grid.Graph[bool]{Grid: cells, Passable: func(floor bool) bool { return floor }}
+	labels, count := graph.Components()
+	if count < 2 {
+		return
+	}
+
+	sizes := make([]int, count)
+	labels.Each(func(_ xy.XY, label int) bool {
+		if label >= 0 {
+			sizes[label]++
+		}
+		return true
+	})
+	largest := 0
+	for label, size := range sizes {
+		if size > sizes[largest] {
+			largest = label
+		}
+	}
+	labels.Each(func(at xy.XY, label int) bool {
+		if label != largest {
+			cells.Set(at, false)
+		}
+		return true
+	})
+}
+
+// placeEnds places a start and a goal on the floor of graph, which must be connected,
+// at least minLength steps apart. The start is first picked by a double sweep, which
+// finds an end of a longest path on floors without loops and usually a good one
+// otherwise. Should no goal be far enough from it, every other floor cell is tried in
+// turn, so a goal is found whenever the floor allows it.
+func placeEnds(
+	r *rand.Rand, graph *grid.Graph[bool], minLength int,
+) (start, goal xy.XY, ok bool) {
+	var floor []xy.XY
+	graph.Grid.Each(func(at xy.XY, isFloor bool) bool {
+		if isFloor {
+			floor = append(floor, at)
+		}
+		return true
+	})
+	if len(floor) < 2 {
+		return xy.XY{}, xy.XY{}, false
+	}
+
+	farthest := -1
+	for node, cost := range astar.Costs(graph.Node(floor[r.Intn(len(floor))])) {
+		if cost > farthest || (cost == farthest && less(node.XY, start)) {
+			start, farthest = node.XY, cost
+		}
+	}
+	if goal, ok := pickGoal(r, graph, start, minLength); ok {
+		return start, goal, true
+	}
+
+	for _, start := range floor {
+		if goal, ok := pickGoal(r, graph, start, minLength); ok {
+			return start, goal, true
+		}
+	}
+	return xy.XY{}, xy.XY{}, false
+}
+
+// pickGoal picks a random floor cell at least minLength steps away from start.
+func pickGoal(
+	r *rand.Rand, graph *grid.Graph[bool], start xy.XY, minLength int,
+) (xy.XY, bool) {
+	var goals []xy.XY
+	for node, cost := range astar.Costs(graph.Node(start)) {
+		if cost >= minLength && node.XY != start {
+			goals = append(goals, node.XY)
+		}
+	}
+	if len(goals) == 0 {
+		return xy.XY{}, false
+	}
+	// map iteration is random, so candidates are sorted before one is picked
+	sortXY(goals)
+	return goals[r.Intn(len(goals))], true
+}
+
+// Rooms is a layout of rectangular rooms, each joined to the previous one by an L-shaped
+// corridor.
+func Rooms(r *rand.Rand, width, height int) [][]bool {
+	cells := walls(width, height)
+	var rooms []xy.Rect
+	for attempt := 0; attempt < 50; attempt++ {
+		w, h := 3+r.Intn(min(6, width-2)), 3+r.Intn(min(6, height-2))
+		if w > width-2 || h > height-2 {
+			continue
+		}
+		room := xy.Rect{Min: xy.XY{X: 1 + r.Intn(width-1-w), Y: 1 + r.Intn(height-1-h)}}
+		room.Max = room.Min.Add(xy.XY{X: w, Y: h})
+
+		// rooms are kept a wall apart, so they do not merge into larger ones
+		one := xy.XY{X: 1, Y: 1}
+		margin := xy.Rect{Min: room.Min.Sub(one), Max: room.Max.Add(one)}
+		overlaps := false
+		for _, other := range rooms {
+			if !margin.Clip(other).Empty() {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+
+		room.Iterate(func(at xy.XY) bool {
+			cells[at.Y][at.X] = true
+			return true
+		})
+		if len(rooms) > 0 {
+			corridor(r, cells, center(rooms[len(rooms)-1]), center(room))
+		}
+		rooms = append(rooms, room)
+	}
+	return cells
+}
+
+// Caves is a layout of caves grown by a cellular automaton: cells start as random walls
+// and floors, and become walls whenever most of their neighbors are.
+func Caves(r *rand.Rand, width, height int) [][]bool {
+	cells := walls(width, height)
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < width-1; x++ {
+			cells[y][x] = r.Float64() >= 0.45
+		}
+	}
+
+	for step := 0; step < 5; step++ {
+		next := walls(width, height)
+		for y := 1; y < height-1; y++ {
+			for x := 1; x < width-1; x++ {
+				wallCount := 0
+				for _, neighbor := range (xy.XY{X: x, Y: y}).Neighbors8() {
+					if !cells[neighbor.Y][neighbor.X] {
+						wallCount++
+					}
+				}
+				next[y][x] = wallCount < 5
+			}
+		}
+		cells = next
+	}
+	return cells
+}
+
+// Maze is a layout of a perfect maze carved by a recursive backtracker, whose corridors
+// are a cell wide. Corridors run along odd rows and columns.
+func Maze(r *rand.Rand, width, height int) [][]bool {
+	cells := walls(width, height)
+	start := xy.XY{X: 1, Y: 1}
+	cells[start.Y][start.X] = true
+
+	stack := []xy.XY{start}
+	for len(stack) > 0 {
+		at := stack[len(stack)-1]
+
+		var unvisited []xy.XY
+		for _, next := range at.Neighbors4() {
+			next = at.Add(next.Sub(at).Scale(2))
+			if next.X > 0 && next.Y > 0 && next.X < width-1 && next.Y < height-1 &&
+				!cells[next.Y][next.X] {
+				unvisited = append(unvisited, next)
+			}
+		}
+		if len(unvisited) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := unvisited[r.Intn(len(unvisited))]
+		between := xy.XY{X: (at.X + next.X) / 2, Y: (at.Y + next.Y) / 2}
+		cells[between.Y][between.X], cells[next.Y][next.X] = true, true
+		stack = append(stack, next)
+	}
+	return cells
+}
+
+// walls returns cells of the given size which are all walls.
+func walls(width, height int) [][]bool {
+	cells := make([][]bool, height)
+	for y := range cells {
+		cells[y] = make([]bool, width)
+	}
+	return cells
+}
+
+// corridor carves an L-shaped corridor from "from" to "to", turning at random either
+// after the horizontal or the vertical stretch.
+func corridor(r *rand.Rand, cells [][]bool, from, to xy.XY) {
+	corner := xy.XY{X: to.X, Y: from.Y}
+	if r.Intn(2) == 0 {
+		corner = xy.XY{X: from.X, Y: to.Y}
+	}
+	for _, stretch := range [...][2]xy.XY{{from, corner}, {corner, to}} {
+		for _, at := range xy.Line(stretch[0], stretch[1]) {
+			cells[at.Y][at.X] = true
+		}
+	}
+}
+
+func center(rect xy.Rect) xy.XY {
+	return xy.XY{X: (rect.Min.X + rect.Max.X - 1) / 2, Y: (rect.Min.Y + rect.Max.Y - 1) / 2}
+}
+
+// less orders coordinates row by row.
+func less(a, b xy.XY) bool {
+	return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+}
+
+func sortXY(points []xy.XY) {
+	sort.Slice(points, func(i, j int) bool {
+		return less(points[i], points[j])
+	})
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,152 @@
+package generate
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// pathLength returns the amount of steps of the shortest path from the dungeon's start
+// to its goal, or -1 if there is none.
+func pathLength(dungeon plan.JSONDungeon) int {
+	rows := make([][]byte, len(dungeon.Grid))
+	for y, row := range dungeon.Grid {
+		rows[y] = []byte(row)
+	}
+	graph := &grid.Graph[byte]{
+		Grid:     grid.FromRows(rows),
+		Passable: func(char byte) bool { return char == ' ' },
+		Cost:     func(byte) int { return 1 },
+	}
+	path, length := astar.FindPath(
+		graph.Node(dungeon.Start), graph.Node(dungeon.Goal), grid.Manhattan[byte](1),
+	)
+	if path == nil {
+		return -1
+	}
+	return length
+}
+
+func TestDungeon(t *testing.T) {
+	layouts := map[string]Layout{"rooms": Rooms, "caves": Caves, "maze": Maze}
+	opts := DefaultDungeonOptions()
+	for name, layout := range layouts {
+		r := rand.New(rand.NewSource(1))
+		for idx := 0; idx < 20; idx++ {
+			dungeon, err := Dungeon(r, layout, opts)
+			if err != nil {
+				t.Fatalf("%s %d: unexpected error: %v", name, idx, err)
+			}
+			if len(dungeon.Grid) != opts.Height || len(dungeon.Grid[0]) != opts.Width {
+				t.Fatalf("%s %d: unexpected size", name, idx)
+			}
+
+			jplan := plan.JSONPlan{
+				MasterSword: xy.XY{X: 0, Y: 0}, LostWoods: xy.XY{X: 1, Y: 0},
+				Start: xy.XY{X: 2, Y: 0}, MainMap: []string{"    "},
+				Dungeons: []plan.JSONDungeon{dungeon},
+			}
+			jplan.Dungeons[0].Entrance = xy.XY{X: 3, Y: 0}
+			if err := jplan.Validate(); err != nil {
+				t.Errorf("%s %d: invalid dungeon: %v", name, idx, err)
+			}
+			if length := pathLength(dungeon); length < opts.MinPathLength {
+				t.Errorf("%s %d: path of %d steps is too short", name, idx, length)
+			}
+		}
+	}
+}
+
+func TestMazeIsPerfect(t *testing.T) {
+	cells := Maze(rand.New(rand.NewSource(3)), 9, 7)
+	floors, edges := 0, 0
+	for y, row := range cells {
+		for x, isFloor := range row {
+			if !isFloor {
+				continue
+			}
+			floors++
+			if x+1 < len(row) && row[x+1] {
+				edges++
+			}
+			if y+1 < len(cells) && cells[y+1][x] {
+				edges++
+			}
+		}
+	}
+	// the 4x3 cells of odd coordinates are joined by 11 corridor cells, and a tree has
+	// one edge less than it has nodes
+	if floors != 4*3+11 || edges != floors-1 {
+		t.Errorf("Expected a spanning tree, got %d floors and %d edges", floors, edges)
+	}
+}
+
+// TestPlaceEndsWithLoops checks that the ends of the longest path are found on a floor
+// with loops, on which a double sweep from some cells misses them.
+func TestPlaceEndsWithLoops(t *testing.T) {
+	rows := []string{
+		"##    ",
+		"#  #  ",
+		"     #",
+		"   #  ",
+		"   ###",
+	}
+	cells := make([][]bool, len(rows))
+	for y, row := range rows {
+		for _, char := range row {
+			cells[y] = append(cells[y], char == ' ')
+		}
+	}
+	graph := &grid.Graph[bool]{
+		Grid:     grid.FromRows(cells),
+		Passable: func(floor bool) bool { return floor },
+		Cost:     func(bool) int { return 1 },
+	}
+
+	const diameter = 9
+	for seed := int64(0); seed < 50; seed++ {
+		start, goal, ok := placeEnds(rand.New(rand.NewSource(seed)), graph, diameter)
+		if !ok {
+			t.Fatalf("seed %d: expected ends %d steps apart to be found", seed, diameter)
+		}
+		_, length := astar.FindPath(
+			graph.Node(start), graph.Node(goal), grid.Manhattan[bool](1),
+		)
+		if length < diameter {
+			t.Fatalf("seed %d: ends are only %d steps apart", seed, length)
+		}
+	}
+}
+
+func TestDungeonIsDeterministic(t *testing.T) {
+	first, err := Dungeon(rand.New(rand.NewSource(7)), Caves, DefaultDungeonOptions())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	second, err := Dungeon(rand.New(rand.NewSource(7)), Caves, DefaultDungeonOptions())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Expected the same seed to yield the same dungeon")
+	}
+}
+
+func TestDungeonErrors(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	opts := DungeonOptions{Width: 8, Height: 8, MinPathLength: 100}
+	if _, err := Dungeon(r, Rooms, opts); !errors.Is(err, ErrTooShort) {
+		t.Errorf("Expected ErrTooShort, got %v", err)
+	}
+
+	opts = DungeonOptions{Width: 2, Height: 8}
+	if _, err := Dungeon(r, Rooms, opts); err == nil {
+		t.Error("Expected an error for a dungeon too small to have walls")
+	}
+}
@@ -0,0 +1,55 @@
+package generate
+
+import "math"
+
+// noise is fractal value noise: random values are placed on the corners of a lattice,
+// interpolated in between, and several octaves of ever finer lattices are summed.
+type noise struct {
+	seed uint64
+	// scale is the length, in cells, of the coarsest lattice's squares.
+	scale   float64
+	octaves int
+}
+
+// at returns the noise's value at (x, y), which lies in [0, 1).
+func (n noise) at(x, y float64) float64 {
+	sum, amplitude, total := 0.0, 1.0, 0.0
+	frequency := 1 / n.scale
+	for octave := 0; octave < n.octaves; octave++ {
+		sum += amplitude * n.smooth(x*frequency, y*frequency, uint64(octave))
+		total += amplitude
+		amplitude /= 2
+		frequency *= 2
+	}
+	return sum / total
+}
+
+// smooth interpolates the lattice values around (x, y).
+func (n noise) smooth(x, y float64, octave uint64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	tx, ty := fade(x-x0), fade(y-y0)
+	ix, iy := int64(x0), int64(y0)
+
+	top := lerp(n.lattice(ix, iy, octave), n.lattice(ix+1, iy, octave), tx)
+	bottom := lerp(n.lattice(ix, iy+1, octave), n.lattice(ix+1, iy+1, octave), tx)
+	return lerp(top, bottom, ty)
+}
+
+// lattice returns the random value of a lattice corner, which lies in [0, 1).
+func (n noise) lattice(x, y int64, octave uint64) float64 {
+	// splitmix64's finalizer mixes the seed and the corner into a well distributed hash
+	h := n.seed ^ uint64(x)*0x9e3779b97f4a7c15 ^ uint64(y)*0xc2b2ae3d27d4eb4f ^ octave<<56
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	h ^= h >> 31
+	return float64(h>>11) / (1 << 53)
+}
+
+// fade eases t, so the noise has no visible creases along the lattice.
+func fade(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
@@ -0,0 +1,216 @@
+// Package generate creates plans procedurally, from a seed, so test maps never run out.
+// The same seed and options always yield the same plan.
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// ErrNoRoom is returned when the points of interest may not be placed on a map as far
+// from each other as required.
+var ErrNoRoom = errors.New("no room for the points of interest")
+
+// deepWater is the character of deep water on a generated overworld's main map.
+const deepWater = '~'
+
+// DeepWater returns the terrain of the lowest cells of a generated overworld. It may not
+// be traversed, so it is declared by the plan's palette whenever it is used.
+func DeepWater() plan.JSONTerrain {
+	return plan.JSONTerrain{
+		Char: string(deepWater), Name: "deep water", Passable: new(bool), Color: "#1a3a80",
+	}
+}
+
+// Options configures the overworlds created by Overworld.
+type Options struct {
+	Width, Height int
+	// Dungeons is the amount of dungeons placed on the overworld.
+	Dungeons int
+	// MinDistance is the least Manhattan distance between any two points of interest.
+	MinDistance int
+	// Scale is the length, in cells, of the noise's coarsest features, which is roughly
+	// the size of islands and mountain ranges.
+	Scale float64
+
+	// Thresholds on the elevation, which lies in [0, 1]. Cells lower than DeepWater are
+	// deep water, lower than Water are water, lower than Sand are sand and higher than
+	// Mountain are mountains. Others are either grass or, if their moisture, which also
+	// lies in [0, 1], is higher than Forest, forest.
+	DeepWater, Water, Sand, Mountain, Forest float64
+
+	// Dungeon creates the dungeon placed behind each entrance, whose entrance is set by
+	// the generator. If nil, RandomDungeon is used.
+	Dungeon func(r *rand.Rand) (plan.JSONDungeon, error)
+}
+
+// DefaultOptions returns the options of overworlds as large as the default plan's, with
+// three dungeons.
+func DefaultOptions() Options {
+	return Options{
+		Width: 42, Height: 42, Dungeons: 3, MinDistance: 8, Scale: 16,
+		DeepWater: 0.12, Water: 0.3, Sand: 0.38, Mountain: 0.78, Forest: 0.55,
+		Dungeon: RandomDungeon,
+	}
+}
+
+// Overworld creates a plan from seed. Elevation and moisture are drawn from noise and
+// mapped to terrains, and the points of interest are placed on grass, sand or forest,
+// reachable from the start and at least MinDistance apart. The returned plan is valid.
+func Overworld(seed int64, opts Options) (plan.JSONPlan, error) {
+	if opts.Width < 1 || opts.Height < 1 || opts.Scale <= 0 {
+		return plan.JSONPlan{}, fmt.Errorf(
+			"invalid dimensions: %dx%d at scale %g", opts.Width, opts.Height, opts.Scale,
+		)
+	}
+	if opts.Dungeons < 0 {
+		return plan.JSONPlan{}, fmt.Errorf("invalid amount of dungeons: %d", opts.Dungeons)
+	}
+	if opts.Dungeon == nil {
+		opts.Dungeon = RandomDungeon
+	}
+	r := rand.New(rand.NewSource(seed))
+	rows := terrainRows(r, opts)
+
+	points, err := placePoints(r, rows, 3+opts.Dungeons, opts.MinDistance)
+	if err != nil {
+		return plan.JSONPlan{}, err
+	}
+
+	jplan := plan.JSONPlan{
+		Start: points[0], LostWoods: points[1], MasterSword: points[2],
+		Version: plan.CurrentVersion, Dungeons: make([]plan.JSONDungeon, 0, opts.Dungeons),
+	}
+	for _, row := range rows {
+		jplan.MainMap = append(jplan.MainMap, string(row))
+	}
+	if usesDeepWater(rows) {
+		jplan.Palette = []plan.JSONTerrain{DeepWater()}
+	}
+	for _, entrance := range points[3:] {
+		dungeon, err := opts.Dungeon(r)
+		if err != nil {
+			return plan.JSONPlan{}, fmt.Errorf("failed to generate a dungeon: %w", err)
+		}
+		dungeon.Entrance = entrance
+		jplan.Dungeons = append(jplan.Dungeons, dungeon)
+	}
+
+	if err := jplan.Validate(); err != nil {
+		return plan.JSONPlan{}, fmt.Errorf("generated an invalid plan: %w", err)
+	}
+	return jplan, nil
+}
+
+// terrainRows draws the elevation and moisture of each cell and maps them to terrains.
+func terrainRows(r *rand.Rand, opts Options) [][]byte {
+	elevation := noise{seed: r.Uint64(), scale: opts.Scale, octaves: 4}
+	moisture := noise{seed: r.Uint64(), scale: opts.Scale, octaves: 3}
+
+	// elevations are stretched over [0, 1], so the thresholds hold on every map
+	elevations := grid.New[float64](opts.Width, opts.Height)
+	low, high := math.Inf(1), math.Inf(-1)
+	elevations.Each(func(at xy.XY, _ float64) bool {
+		value := elevation.at(float64(at.X), float64(at.Y))
+		elevations.Set(at, value)
+		low, high = math.Min(low, value), math.Max(high, value)
+		return true
+	})
+
+	rows := make([][]byte, opts.Height)
+	for y := range rows {
+		rows[y] = make([]byte, opts.Width)
+		for x := range rows[y] {
+			height := 0.5
+			if high > low {
+				height = (elevations.At(xy.XY{X: x, Y: y}) - low) / (high - low)
+			}
+
+			switch {
+			case height < opts.DeepWater:
+				rows[y][x] = deepWater
+			case height < opts.Water:
+				rows[y][x] = byte(plan.Water.Char())
+			case height < opts.Sand:
+				rows[y][x] = byte(plan.Sand.Char())
+			case height > opts.Mountain:
+				rows[y][x] = byte(plan.Mountain.Char())
+			case moisture.at(float64(x), float64(y)) > opts.Forest:
+				rows[y][x] = byte(plan.Forest.Char())
+			default:
+				rows[y][x] = byte(plan.Grass.Char())
+			}
+		}
+	}
+	return rows
+}
+
+// placePoints places count points of interest on rows, the first of which is the start.
+// The others are reachable from it and every point is at least minDistance away from
+// the others.
+func placePoints(r *rand.Rand, rows [][]byte, count, minDistance int) ([]xy.XY, error) {
+	graph := grid.Graph[byte]{
+		Grid:     grid.FromRows(rows),
+		Passable: func(char byte) bool { return char != deepWater },
+		Cost:     func(byte) int { return 1 },
+	}
+	var candidates []xy.XY
+	graph.Grid.Each(func(at xy.XY, char byte) bool {
+		switch rune(char) {
+		case plan.Grass.Char(), plan.Sand.Char(), plan.Forest.Char():
+			candidates = append(candidates, at)
+		}
+		return true
+	})
+
+	// each attempt starts elsewhere, as the start's surroundings may be too small
+	const attempts = 20
+	for attempt := 0; attempt < attempts && len(candidates) > 0; attempt++ {
+		start := candidates[r.Intn(len(candidates))]
+		reachable := graph.Reachable(start)
+
+		points := []xy.XY{start}
+		for _, idx := range r.Perm(len(candidates)) {
+			if len(points) == count {
+				break
+			}
+			at := candidates[idx]
+			_, isReachable := reachable[at]
+			if isReachable && farFrom(at, points, minDistance) {
+				points = append(points, at)
+			}
+		}
+		if len(points) == count {
+			return points, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d points, %d cells apart", ErrNoRoom, count, minDistance)
+}
+
+// farFrom reports whether at is at least minDistance away from every point. A point is
+// never far from itself.
+func farFrom(at xy.XY, points []xy.XY, minDistance int) bool {
+	for _, point := range points {
+		if point == at || xy.Manhattan(at, point) < minDistance {
+			return false
+		}
+	}
+	return true
+}
+
+func usesDeepWater(rows [][]byte) bool {
+	for _, row := range rows {
+		for _, char := range row {
+			if char == deepWater {
+				return true
+			}
+		}
+	}
+	return false
+}
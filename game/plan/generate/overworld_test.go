@@ -0,0 +1,105 @@
+package generate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/game/mission"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestOverworld(t *testing.T) {
+	opts := DefaultOptions()
+	terrains := make(map[rune]int)
+	for seed := int64(0); seed < 30; seed++ {
+		jplan, err := Overworld(seed, opts)
+		if err != nil {
+			t.Fatalf("Seed %d: unexpected error: %v", seed, err)
+		}
+		if len(jplan.MainMap) != opts.Height || len(jplan.MainMap[0]) != opts.Width {
+			t.Fatalf("Seed %d: unexpected size", seed)
+		}
+		if len(jplan.Dungeons) != opts.Dungeons {
+			t.Fatalf("Seed %d: expected %d dungeons", seed, opts.Dungeons)
+		}
+
+		points := []xy.XY{jplan.Start, jplan.LostWoods, jplan.MasterSword}
+		for _, dungeon := range jplan.Dungeons {
+			points = append(points, dungeon.Entrance)
+		}
+		for i := range points {
+			for j := i + 1; j < len(points); j++ {
+				if xy.Manhattan(points[i], points[j]) < opts.MinDistance {
+					t.Errorf("Seed %d: %v and %v are too close", seed, points[i], points[j])
+				}
+			}
+		}
+
+		gamePlan := jplan.ToPlan()
+		if _, err := mission.Analyze(&gamePlan); err != nil {
+			t.Errorf("Seed %d: unsolvable plan: %v", seed, err)
+		}
+		for _, row := range jplan.MainMap {
+			for _, char := range row {
+				terrains[char]++
+			}
+		}
+	}
+
+	for _, char := range " @%_*~" {
+		if terrains[char] == 0 {
+			t.Errorf("No %q terrain was ever generated", char)
+		}
+	}
+}
+
+func TestOverworldIsDeterministic(t *testing.T) {
+	first, err := Overworld(42, DefaultOptions())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	second, err := Overworld(42, DefaultOptions())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Expected the same seed to yield the same plan")
+	}
+
+	other, err := Overworld(43, DefaultOptions())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if strings.Join(other.MainMap, "") == strings.Join(first.MainMap, "") {
+		t.Error("Expected different seeds to yield different maps")
+	}
+}
+
+func TestOverworldErrors(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Width, opts.Height = 6, 6
+	if _, err := Overworld(1, opts); !errors.Is(err, ErrNoRoom) {
+		t.Errorf("Expected ErrNoRoom, got %v", err)
+	}
+
+	opts.Width = 0
+	if _, err := Overworld(1, opts); err == nil {
+		t.Error("Expected an error for an empty map")
+	}
+
+	opts = DefaultOptions()
+	opts.Dungeons = -1
+	if _, err := Overworld(1, opts); err == nil {
+		t.Error("Expected an error for a negative amount of dungeons")
+	}
+}
+
+func TestDeepWaterIsFresh(t *testing.T) {
+	terrain := DeepWater()
+	*terrain.Passable = true
+	if *DeepWater().Passable {
+		t.Error("Expected changes to a returned terrain not to leak into later ones")
+	}
+}
@@ -0,0 +1,81 @@
+package plan
+
+import (
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// HexPlan is a map made of pointy-top hexagonal terrains. Its grid is stored in the odd-r
+// offset layout, in which odd rows are shoved half a block to the right.
+type HexPlan struct {
+	// Grid is the map's terrain grid.
+	Grid [][]Terrain
+
+	// Start is where the agent starts and Goal is where it must go.
+	Start xy.XY
+	Goal  xy.XY
+}
+
+// Graph returns the graph of the plan's terrains, on which each hexagon neighbors six
+// others.
+func (hp *HexPlan) Graph() *grid.Graph[Terrain] {
+	return &grid.Graph[Terrain]{
+		Grid:         grid.FromRows(hp.Grid),
+		Passable:     Terrain.Traversable,
+		Cost:         Terrain.Cost,
+		Neighborhood: grid.Hexagonal,
+	}
+}
+
+// Reachable reports whether the goal may be reached from the start.
+func (hp *HexPlan) Reachable() bool {
+	_, isReached := hp.Graph().Reachable(hp.Start)[hp.Goal]
+	return isReached
+}
+
+// JSONHexPlan represents a hexagonal map in a JSON format. Its rows use the same
+// characters as the main map of a JSONPlan.
+type JSONHexPlan struct {
+	Start xy.XY    `json:"start"`
+	Goal  xy.XY    `json:"goal"`
+	Rows  []string `json:"rows"`
+}
+
+func (jhp JSONHexPlan) ToHexPlan() HexPlan {
+	plan := HexPlan{Start: jhp.Start, Goal: jhp.Goal}
+
+	tm := defaultGridMap()
+	for idx, str := range jhp.Rows {
+		plan.Grid = append(plan.Grid, make([]Terrain, 0, len(str)))
+
+		for _, rune := range str {
+			plan.Grid[idx] = append(plan.Grid[idx], tm[rune])
+		}
+	}
+
+	return plan
+}
+
+// Validate checks whether the plan is valid. Every problem found is reported at once,
+// through ValidationErrors.
+func (jhp JSONHexPlan) Validate() error {
+	var v validator
+
+	tm := defaultGridMap()
+	known := func(char byte) bool {
+		_, isKnown := tm[rune(char)]
+		return isKnown
+	}
+	passable := func(char byte) bool {
+		return tm[rune(char)].Traversable()
+	}
+
+	rows := jhp.Rows
+	if !v.rows("rows", rows, known) {
+		rows = nil
+	}
+	v.coordinate("start", jhp.Start, rows, passable)
+	v.coordinate("goal", jhp.Goal, rows, passable)
+
+	return v.err()
+}
@@ -0,0 +1,29 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestHexPlanReachable(t *testing.T) {
+	wall := Terrain{name: "wall", char: '#'}
+	hexPlan := HexPlan{
+		Grid: [][]Terrain{
+			{Grass, wall, Grass},
+			{wall, wall, Grass},
+			{Grass, Grass, Grass},
+		},
+		Start: xy.XY{X: 0, Y: 0},
+		Goal:  xy.XY{X: 2, Y: 2},
+	}
+	if hexPlan.Reachable() {
+		t.Error("Expected the goal to be walled off from the start")
+	}
+
+	// (0, 1) neighbors both (0, 0) and (0, 2), so opening it joins both sides
+	hexPlan.Grid[1][0] = Grass
+	if !hexPlan.Reachable() {
+		t.Error("Expected the goal to be reachable")
+	}
+}
@@ -3,28 +3,40 @@ package plan
 import (
 	"fmt"
 
-	"github.com/agstrc/heuristic-search/images"
 	"github.com/agstrc/heuristic-search/xy"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 // JSONPlan represents the game's map in a JSON format.
 type JSONPlan struct {
+	// Version is the version of the plan's format. Plans read by ParseJSON are always of
+	// CurrentVersion.
+	Version int `json:"version"`
+
 	MasterSword xy.XY `json:"master_sword"`
 	LostWoods   xy.XY `json:"lost_woods"`
 	Start       xy.XY `json:"start"`
 
-	MainMap  []string      `json:"main_map"`
-	Dungeons []JSONDungeon `json:"dungeons"`
+	// Palette declares terrains in addition to the predefined ones. A terrain whose
+	// character is the same as a predefined terrain's replaces it.
+	Palette []JSONTerrain `json:"palette,omitempty"`
+	MainMap []string      `json:"main_map"`
+	// Dungeons may be left out, in which case the mission only leads to the Lost Woods.
+	Dungeons []JSONDungeon `json:"dungeons,omitempty"`
+	// Teleporters join blocks of the main map.
+	Teleporters []JSONTeleporter `json:"teleporters,omitempty"`
+
+	// Dir is the directory which the palette's image paths are relative to. It is usually
+	// the directory of the plan's file.
+	Dir string `json:"-"`
 }
 
 func (jp JSONPlan) ToPlan() Plan {
-	plan := Plan{Sword: jp.MasterSword, Gate: jp.LostWoods}
+	plan := Plan{Start: jp.Start, Sword: jp.MasterSword, Gate: jp.LostWoods}
 	grid := [][]Terrain{}
 
 	tm := jp.gridMap()
 	for idx, str := range jp.MainMap {
-		grid = append(grid, make([]Terrain, 0, MainMapSize))
+		grid = append(grid, make([]Terrain, 0, len(str)))
 
 		for _, rune := range str {
 			grid[idx] = append(grid[idx], tm[rune])
@@ -32,167 +44,200 @@ func (jp JSONPlan) ToPlan() Plan {
 	}
 	plan.Grid = grid
 
-	dtm := jp.Dungeons[0].gridMap()
-	images := [...]*ebiten.Image{images.Virtue1, images.Virtue2, images.Virtue3}
-	for idx, jsonDungeon := range jp.Dungeons {
-		dungeon := Dungeon{
-			Entrance: jsonDungeon.Entrance,
-			Start:    jsonDungeon.Start,
-			GoalXY:   jsonDungeon.Goal,
-			GoalImg:  images[idx],
-		}
-
-		for idx, str := range jsonDungeon.Grid {
-			dungeon.Grid = append(dungeon.Grid, make([]DungeonTerrain, 0, DungeonSize))
-
-			for _, rune := range str {
-				dungeon.Grid[idx] = append(dungeon.Grid[idx], dtm[rune])
-			}
-
-		}
-
-		plan.Dungeons[idx] = dungeon
+	for _, jsonDungeon := range jp.Dungeons {
+		plan.Dungeons = append(plan.Dungeons, jsonDungeon.toDungeon())
+	}
+	for _, teleporter := range jp.Teleporters {
+		plan.Teleporters = append(plan.Teleporters, Teleporter(teleporter))
 	}
 
 	return plan
 }
 
+// Validate checks whether the plan is valid. Every problem found is reported at once,
+// through ValidationErrors.
 func (jp JSONPlan) Validate() error {
-	if err := jp.validateMainMap(); err != nil {
-		return fmt.Errorf("main map is invalid: %w", err)
+	var v validator
+	jp.validatePalette(&v)
+
+	passable := jp.passableChars()
+	known := func(char byte) bool {
+		_, isKnown := passable[rune(char)]
+		return isKnown
 	}
-	if err := jp.validateCoordinates(); err != nil {
-		return fmt.Errorf("coordinates are invalid: %w", err)
+	isPassable := func(char byte) bool {
+		return passable[rune(char)]
 	}
-	if err := jp.validateDungeons(); err != nil {
-		return fmt.Errorf("dungeons are invalid: %w", err)
+
+	mainMap := jp.MainMap
+	if !v.rows("main_map", mainMap, known) {
+		mainMap = nil
 	}
+	v.coordinate("master_sword", jp.MasterSword, mainMap, isPassable)
+	v.coordinate("lost_woods", jp.LostWoods, mainMap, isPassable)
+	v.coordinate("start", jp.Start, mainMap, isPassable)
 
-	return nil
-}
+	points := []pointOfInterest{
+		{"start", jp.Start}, {"lost_woods", jp.LostWoods}, {"master_sword", jp.MasterSword},
+	}
+	for idx, dungeon := range jp.Dungeons {
+		path := fmt.Sprintf("dungeons[%d]", idx)
+		v.coordinate(path+".entrance", dungeon.Entrance, mainMap, isPassable)
+		dungeon.validate(&v, path)
 
-func (jp JSONPlan) validateCoordinates() error {
-	for _, coord := range [...]xy.XY{jp.MasterSword, jp.LostWoods, jp.Start} {
-		if coord.X < 0 || coord.X >= MainMapSize {
-			return fmt.Errorf("invalid coordinate pair: (%d, %d)", coord.X, coord.Y)
-		}
-		if coord.Y < 0 || coord.Y >= MainMapSize {
-			return fmt.Errorf("invalid coordinate pair: (%d, %d)", coord.X, coord.Y)
-		}
+		points = append(points, pointOfInterest{path + ".entrance", dungeon.Entrance})
 	}
-	return nil
-}
+	for idx, teleporter := range jp.Teleporters {
+		path := fmt.Sprintf("teleporters[%d]", idx)
+		v.coordinate(path+".from", teleporter.From, mainMap, isPassable)
+		v.coordinate(path+".to", teleporter.To, mainMap, isPassable)
 
-func (jp JSONPlan) validateMainMap() error {
-	if len(jp.MainMap) != MainMapSize {
-		return fmt.Errorf("invalid main map array length: %d", len(jp.MainMap))
+		points = append(points,
+			pointOfInterest{path + ".from", teleporter.From},
+			pointOfInterest{path + ".to", teleporter.To},
+		)
 	}
+	v.overlaps(points...)
 
-	tm := jp.gridMap()
+	return v.err()
+}
 
-	for idx, str := range jp.MainMap {
-		if len(str) != MainMapSize {
-			return fmt.Errorf("row (index %d) has invalid length: %d", idx, len(str))
-		}
+func (jp JSONPlan) validatePalette(v *validator) {
+	chars := make(map[string]struct{}, len(jp.Palette))
+	for idx, terrain := range jp.Palette {
+		path := fmt.Sprintf("palette[%d]", idx)
+		terrain.validate(v, path, jp.Dir)
 
-		for _, rune := range str {
-			if _, inMap := tm[rune]; !inMap {
-				return fmt.Errorf("row (index %d) has unknown character: %s", idx, string(rune))
-			}
+		if _, isDuplicate := chars[terrain.Char]; isDuplicate {
+			v.add(path+".char", -1, -1, fmt.Errorf("%w: %q", ErrDuplicateChar, terrain.Char))
 		}
+		chars[terrain.Char] = struct{}{}
 	}
-
-	return nil
 }
 
-func (jp JSONPlan) validateDungeons() error {
-	if len(jp.Dungeons) != DungeonCount {
-		return fmt.Errorf("invalid amount of dungeons (have %d, want %d)", len(jp.Dungeons), DungeonCount)
+// gridMap maps each character of the main map to its terrain. The plan's palette must
+// be valid.
+func (jp JSONPlan) gridMap() map[rune]Terrain {
+	tm := defaultGridMap()
+	for _, terrain := range jp.Palette {
+		tm[rune(terrain.Char[0])] = terrain.toTerrain(jp.Dir)
 	}
+	return tm
+}
 
-	for idx, dungeon := range jp.Dungeons {
-		if err := dungeon.validate(); err != nil {
-			return fmt.Errorf("dungeon (index %d) is invalid: %w", idx, err)
+// passableChars maps each character of the main map to whether its terrain is
+// traversable, without loading the palette's images.
+func (jp JSONPlan) passableChars() map[rune]bool {
+	passable := make(map[rune]bool)
+	for char, terrain := range defaultGridMap() {
+		passable[char] = terrain.Traversable()
+	}
+	for _, terrain := range jp.Palette {
+		if len(terrain.Char) == 1 {
+			passable[rune(terrain.Char[0])] = terrain.passable()
 		}
 	}
-	return nil
+	return passable
 }
 
-func (JSONPlan) gridMap() map[rune]Terrain {
-	return map[rune]Terrain{
-		'@': Forest, ' ': Grass,
-		'%': Mountain, '_': Sand,
-		'*': Water,
+// defaultGridMap maps the character of each predefined terrain to it.
+func defaultGridMap() map[rune]Terrain {
+	tm := make(map[rune]Terrain)
+	for _, terrain := range DefaultPalette() {
+		tm[terrain.Char()] = terrain
 	}
+	return tm
+}
+
+// JSONTeleporter represents a teleporter of the main map in a JSON format. A teleporter
+// leads both ways, unless it is a one-way warp.
+type JSONTeleporter struct {
+	From   xy.XY `json:"from"`
+	To     xy.XY `json:"to"`
+	OneWay bool  `json:"one_way,omitempty"`
 }
 
 // JSONDungeon represents the game's dungeon in a JSON format.
 type JSONDungeon struct {
-	Grid []string `json:"grid"`
-
 	Entrance xy.XY `json:"entrance"`
 	Start    xy.XY `json:"start"`
 	Goal     xy.XY `json:"goal"`
+
+	Grid []string `json:"grid"`
 }
 
-func (jd JSONDungeon) validate() error {
-	if err := jd.validateGrid(); err != nil {
-		return fmt.Errorf("grid is invalid: %w", err)
+// validate validates the dungeon, which is found at path. Its entrance is validated
+// along with the main map.
+func (jd JSONDungeon) validate(v *validator, path string) {
+	tm := jd.gridMap()
+	known := func(char byte) bool {
+		_, isKnown := tm[rune(char)]
+		return isKnown
 	}
-	if err := jd.validateCoordinates(); err != nil {
-		return fmt.Errorf("coordinates are invalid: %w", err)
+	passable := func(char byte) bool {
+		return tm[rune(char)].Traversable()
 	}
-	return nil
-}
-
-func (jd JSONDungeon) validateCoordinates() error {
-	for _, coord := range [...]xy.XY{jd.Start, jd.Goal} {
-		if coord.X < 0 || coord.X >= DungeonSize {
-			return fmt.Errorf("invalid coordinate pair: (%d, %d)", coord.X, coord.Y)
-		}
-		if coord.Y < 0 || coord.Y >= DungeonSize {
-			return fmt.Errorf("invalid coordinate pair: (%d, %d)", coord.X, coord.Y)
-		}
 
-		if jd.Grid[coord.Y][coord.X] == '#' {
-			return fmt.Errorf("coordinate pair (%d, %d) is on non traversable block", coord.X, coord.Y)
-		}
+	rows := jd.Grid
+	if !v.rows(path+".grid", rows, known) {
+		rows = nil
 	}
+	v.coordinate(path+".start", jd.Start, rows, passable)
+	v.coordinate(path+".goal", jd.Goal, rows, passable)
+	v.overlaps(pointOfInterest{path + ".start", jd.Start}, pointOfInterest{path + ".goal", jd.Goal})
 
-	if jd.Entrance.X < 0 || jd.Entrance.X >= MainMapSize {
-		return fmt.Errorf("invalid entrance coordinates: (%d, %d)", jd.Entrance.X, jd.Entrance.Y)
-	}
-	if jd.Entrance.Y < 0 || jd.Entrance.Y >= MainMapSize {
-		return fmt.Errorf("invalid entrance coordinates: (%d, %d)", jd.Entrance.X, jd.Entrance.Y)
+	bounds := rowsBounds(rows)
+	if rows != nil && bounds.Contains(jd.Start) && bounds.Contains(jd.Goal) {
+		jd.validateRoundTrip(v, path)
 	}
-
-	return nil
 }
 
-func (jd JSONDungeon) validateGrid() error {
-	if len(jd.Grid) != DungeonSize {
-		return fmt.Errorf("invalid dungeon map array length: %d", len(jd.Grid))
+// validateRoundTrip checks whether the goal may be reached from the start, and the start
+// may be reached back from the goal. Either may be prevented by doors whose keys are out
+// of reach or by ledges.
+func (jd JSONDungeon) validateRoundTrip(v *validator, path string) {
+	dungeon := jd.toDungeon()
+	switch goal, back := dungeon.Reachable(); {
+	case !goal:
+		v.add(path+".goal", jd.Goal.Y, jd.Goal.X, fmt.Errorf(
+			"%w: (%d, %d)", ErrUnreachable, jd.Start.X, jd.Start.Y,
+		))
+	case !back:
+		v.add(path+".start", jd.Start.Y, jd.Start.X, fmt.Errorf(
+			"%w: the way back from (%d, %d)", ErrUnreachable, jd.Goal.X, jd.Goal.Y,
+		))
 	}
+}
 
-	tm := jd.gridMap()
-
-	for idx, str := range jd.Grid {
-		if len(str) != DungeonSize {
-			return fmt.Errorf("row (index %d) has invalid length: %d", idx, len(str))
+// toDungeon converts the dungeon, whose grid must be valid, into a Dungeon.
+func (jd JSONDungeon) toDungeon() Dungeon {
+	dungeon := Dungeon{Entrance: jd.Entrance, Start: jd.Start, GoalXY: jd.Goal}
+	for _, str := range jd.Grid {
+		row := make([]DungeonTerrain, 0, len(str))
+		for idx := 0; idx < len(str); idx++ {
+			row = append(row, DungeonTerrain(str[idx]))
 		}
+		dungeon.Grid = append(dungeon.Grid, row)
+	}
+	return dungeon
+}
 
-		for _, rune := range str {
-			if _, inMap := tm[rune]; !inMap {
-				return fmt.Errorf("row (index %d) has unknown character: %v", idx, rune)
-			}
+// gridMap maps each character of a dungeon's grid to its terrain.
+func (JSONDungeon) gridMap() map[rune]DungeonTerrain {
+	tm := make(map[rune]DungeonTerrain)
+	for char := rune(0); char <= '~'; char++ {
+		if terrain := DungeonTerrain(char); terrain.Traversable() || terrain == NonTraversable {
+			tm[char] = terrain
 		}
 	}
-
-	return nil
+	return tm
 }
 
-func (JSONDungeon) gridMap() map[rune]DungeonTerrain {
-	return map[rune]DungeonTerrain{
-		'#': NonTraversable, ' ': Traversable,
+// rowsBounds returns the bounds of a grid made of rows, assuming all rows are as long as
+// the first one.
+func rowsBounds(rows []string) xy.Rect {
+	if len(rows) == 0 {
+		return xy.Rect{}
 	}
+	return xy.RectOf(len(rows[0]), len(rows))
 }
@@ -1,8 +1,111 @@
 package plan
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
 
 func TestDefaulJSONPlan(t *testing.T) {
 	// if it errors out, it'll panic; therefore a simple call is enough to test it
 	DefaultJSONPlan()
 }
+
+func TestDefaultJSONHexPlan(t *testing.T) {
+	DefaultJSONHexPlan()
+}
+
+func TestValidateDimensions(t *testing.T) {
+	jplan := JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 1, Y: 0},
+		Start:       xy.XY{X: 4, Y: 1},
+		MainMap:     []string{"@  _*", "  %  "},
+		Dungeons: []JSONDungeon{{
+			Grid:     []string{"  ", "# "},
+			Entrance: xy.XY{X: 2, Y: 1},
+			Start:    xy.XY{X: 0, Y: 0},
+			Goal:     xy.XY{X: 1, Y: 1},
+		}},
+	}
+	if err := jplan.Validate(); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	plan := jplan.ToPlan()
+	if len(plan.Grid) != 2 || len(plan.Grid[0]) != 5 || len(plan.Dungeons) != 1 {
+		t.Fatal("Plan dimensions differ from the JSON plan")
+	}
+
+	jplan.Start = xy.XY{X: 5, Y: 1}
+	if err := jplan.Validate(); err == nil {
+		t.Fatal("Expected start out of the main map to be invalid")
+	}
+	jplan.Start = xy.XY{X: 4, Y: 1}
+
+	jplan.MainMap = []string{"@  _*", "  % "}
+	if err := jplan.Validate(); err == nil {
+		t.Fatal("Expected ragged main map to be invalid")
+	}
+}
+
+func TestPalette(t *testing.T) {
+	impassable := false
+	jplan := JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 2, Y: 0},
+		Start:       xy.XY{X: 2, Y: 1},
+		Palette: []JSONTerrain{
+			{Char: "=", Name: "road", Cost: 5, Color: "#a08060"},
+			{Char: "~", Name: "swamp", Cost: 50, Passable: &impassable, Color: "#3b5d38"},
+			{Char: "@", Name: "dense forest", Cost: 300, Color: "#0f3010"},
+		},
+		MainMap: []string{"= @", "~~ "},
+	}
+	if err := jplan.Validate(); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	plan := jplan.ToPlan()
+	road, swamp, forest := plan.Grid[0][0], plan.Grid[1][0], plan.Grid[0][2]
+	if road.Name() != "road" || road.Cost() != 5 || !road.Traversable() {
+		t.Error("Road differs from its palette entry")
+	}
+	if swamp.Name() != "swamp" || swamp.Traversable() {
+		t.Error("Swamp should not be traversable")
+	}
+	if forest.Cost() != 300 {
+		t.Error("Palette should replace the predefined forest")
+	}
+	if plan.Grid[0][1] != Grass {
+		t.Error("Predefined terrains should remain available")
+	}
+	if minCost := MinCost(plan.Grid); minCost != 5 {
+		t.Errorf("Expected minimum cost 5, got %d", minCost)
+	}
+
+	invalid := []struct {
+		name   string
+		modify func(*JSONPlan)
+	}{
+		{"start on impassable", func(jp *JSONPlan) { jp.Start = xy.XY{X: 1, Y: 1} }},
+		{"duplicate char", func(jp *JSONPlan) { jp.Palette = append(jp.Palette, jp.Palette[0]) }},
+		{"bad color", func(jp *JSONPlan) { jp.Palette[0].Color = "brown" }},
+		{"no appearance", func(jp *JSONPlan) { jp.Palette[0].Color = "" }},
+		{"missing image", func(jp *JSONPlan) {
+			jp.Palette[0].Color, jp.Palette[0].Image = "", "missing.png"
+		}},
+		{"long char", func(jp *JSONPlan) { jp.Palette[0].Char = "==" }},
+		{"negative cost", func(jp *JSONPlan) { jp.Palette[0].Cost = -1 }},
+		{"unknown char", func(jp *JSONPlan) { jp.MainMap[0] = "=?@" }},
+	}
+	for _, test := range invalid {
+		modified := jplan
+		modified.Palette = append([]JSONTerrain(nil), jplan.Palette...)
+		modified.MainMap = append([]string(nil), jplan.MainMap...)
+		test.modify(&modified)
+
+		if err := modified.Validate(); err == nil {
+			t.Errorf("Expected %s to be invalid", test.name)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// ToJSONPlan converts the plan back into a JSON plan, so it may be saved once modified or
+// generated in code. Each terrain which is not predefined is declared by the palette,
+// sorted by character, and image paths are kept as they were resolved when the plan was
+// loaded, so the JSON plan's Dir is empty.
+//
+// Converting a valid JSON plan to a plan and back yields the same JSON plan, as long as
+// its palette is sorted, only holds terrains used by the main map and image paths are
+// not relative to Dir. An error is returned if two different terrains share a character,
+// or if a terrain has none.
+func (p Plan) ToJSONPlan() (JSONPlan, error) {
+	jplan := JSONPlan{
+		Version:     CurrentVersion,
+		MasterSword: p.Sword,
+		LostWoods:   p.Gate,
+		Start:       p.Start,
+		MainMap:     make([]string, 0, len(p.Grid)),
+		Dungeons:    make([]JSONDungeon, 0, len(p.Dungeons)),
+	}
+
+	predefined := defaultGridMap()
+	terrains := make(map[rune]Terrain)
+	for y, row := range p.Grid {
+		var str strings.Builder
+		for x, terrain := range row {
+			if terrain.char < ' ' || terrain.char > '~' {
+				return JSONPlan{}, fmt.Errorf(
+					"%w: terrain %q at (%d, %d) has no ASCII character",
+					ErrInvalidTerrain, terrain.name, x, y,
+				)
+			}
+			if other, isKnown := terrains[terrain.char]; isKnown && other != terrain {
+				return JSONPlan{}, fmt.Errorf(
+					"%w: %q is shared by terrains %q and %q",
+					ErrDuplicateChar, terrain.char, other.name, terrain.name,
+				)
+			}
+			terrains[terrain.char] = terrain
+			str.WriteRune(terrain.char)
+		}
+		jplan.MainMap = append(jplan.MainMap, str.String())
+	}
+
+	for char, terrain := range terrains {
+		if predefined[char] != terrain {
+			jplan.Palette = append(jplan.Palette, terrain.toJSONTerrain())
+		}
+	}
+	sort.Slice(jplan.Palette, func(i, j int) bool {
+		return jplan.Palette[i].Char < jplan.Palette[j].Char
+	})
+
+	for _, dungeon := range p.Dungeons {
+		jplan.Dungeons = append(jplan.Dungeons, dungeon.toJSONDungeon())
+	}
+	for _, teleporter := range p.Teleporters {
+		jplan.Teleporters = append(jplan.Teleporters, JSONTeleporter(teleporter))
+	}
+	return jplan, nil
+}
+
+// MarshalJSON encodes the plan as its JSON plan.
+func (p Plan) MarshalJSON() ([]byte, error) {
+	jplan, err := p.ToJSONPlan()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jplan)
+}
+
+// inlineCoordinate matches a coordinate pair which json.MarshalIndent spread over
+// several lines.
+var inlineCoordinate = regexp.MustCompile(`\{\n\s*"x": (-?\d+),\n\s*"y": (-?\d+)\n\s*\}`)
+
+// FormatJSON encodes jp in the layout of the default plan's file, indented by four
+// spaces, with a row of a map per line and each coordinate pair on a single line. The
+// output of a given plan is always the same, and is of CurrentVersion.
+func FormatJSON(jp JSONPlan) ([]byte, error) {
+	jp.Version = CurrentVersion
+	data, err := json.MarshalIndent(jp, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	data = inlineCoordinate.ReplaceAll(data, []byte(`{"x": $1,"y": $2}`))
+	return append(data, '\n'), nil
+}
+
+func (t Terrain) toJSONTerrain() JSONTerrain {
+	jterrain := JSONTerrain{Char: string(t.char), Name: t.name, Cost: t.cost, Image: t.image}
+	if !t.passable {
+		jterrain.Passable = &t.passable
+	}
+	for _, direction := range xy.Directions4 {
+		if offset, isOneWay := t.Direction(); isOneWay && offset == direction.Offset() {
+			jterrain.Direction = direction.String()
+		}
+	}
+	if fill, ok := t.Color(); ok {
+		jterrain.Color = fmt.Sprintf("#%02x%02x%02x", fill.R, fill.G, fill.B)
+	}
+	return jterrain
+}
+
+func (d Dungeon) toJSONDungeon() JSONDungeon {
+	jdungeon := JSONDungeon{
+		Grid:     make([]string, 0, len(d.Grid)),
+		Entrance: d.Entrance,
+		Start:    d.Start,
+		Goal:     d.GoalXY,
+	}
+	for _, row := range d.Grid {
+		var str strings.Builder
+		for _, terrain := range row {
+			str.WriteByte(byte(terrain))
+		}
+		jdungeon.Grid = append(jdungeon.Grid, str.String())
+	}
+	return jdungeon
+}
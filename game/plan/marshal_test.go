@@ -0,0 +1,219 @@
+package plan
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/quick"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// canonicalPlan is a random valid JSON plan, in the form which ToJSONPlan returns.
+type canonicalPlan struct {
+	JSONPlan
+}
+
+// customTerrains may be picked by generated palettes. The forest replaces the
+// predefined one.
+var customTerrains = []JSONTerrain{
+	{Char: "=", Name: "road", Cost: 5, Color: "#a08060"},
+	{Char: "~", Name: "swamp", Cost: 0, Color: "#3b5d38"},
+	{Char: "@", Name: "dense forest", Cost: 300, Color: "#000000"},
+	{Char: "a", Name: "ash", Cost: 70, Color: "#5a5a5a"},
+	{Char: "v", Name: "waterfall", Cost: 30, Direction: "south", Color: "#5080e0"},
+}
+
+func (canonicalPlan) Generate(r *rand.Rand, size int) reflect.Value {
+	impassable := false
+	palette := make(map[byte]JSONTerrain)
+	chars := []byte{' ', '@', '%', '_', '*'}
+	for _, terrain := range customTerrains {
+		if r.Intn(2) == 0 {
+			continue
+		}
+		if r.Intn(2) == 0 {
+			terrain.Passable = &impassable
+		}
+		palette[terrain.Char[0]] = terrain
+		chars = append(chars, terrain.Char[0])
+	}
+	passable := func(char byte) bool {
+		terrain, isCustom := palette[char]
+		return !isCustom || terrain.passable()
+	}
+
+	dungeons, teleporters := r.Intn(3), r.Intn(2)
+	width, height := 3+r.Intn(8), 3+r.Intn(8)
+	rows := make([][]byte, height)
+	for y := range rows {
+		rows[y] = make([]byte, width)
+		for x := range rows[y] {
+			rows[y][x] = chars[r.Intn(len(chars))]
+		}
+	}
+
+	// points of interest lie on distinct, passable cells
+	cells := r.Perm(width * height)[:3+dungeons+2*teleporters]
+	points := make([]xy.XY, len(cells))
+	for idx, cell := range cells {
+		points[idx] = xy.XY{X: cell % width, Y: cell / width}
+		if row := rows[points[idx].Y]; !passable(row[points[idx].X]) {
+			row[points[idx].X] = ' '
+		}
+	}
+
+	jplan := JSONPlan{
+		Start: points[0], LostWoods: points[1], MasterSword: points[2],
+		Version: CurrentVersion, Dungeons: make([]JSONDungeon, 0, dungeons),
+	}
+	used := make(map[byte]bool)
+	for _, row := range rows {
+		jplan.MainMap = append(jplan.MainMap, string(row))
+		for _, char := range row {
+			used[char] = true
+		}
+	}
+	for char, terrain := range palette {
+		if used[char] {
+			jplan.Palette = append(jplan.Palette, terrain)
+		}
+	}
+	sort.Slice(jplan.Palette, func(i, j int) bool {
+		return jplan.Palette[i].Char < jplan.Palette[j].Char
+	})
+
+	for _, entrance := range points[3 : 3+dungeons] {
+		jplan.Dungeons = append(jplan.Dungeons, randomDungeon(r, entrance))
+	}
+	for ends := points[3+dungeons:]; len(ends) > 0; ends = ends[2:] {
+		jplan.Teleporters = append(jplan.Teleporters, JSONTeleporter{
+			From: ends[0], To: ends[1], OneWay: r.Intn(2) == 0,
+		})
+	}
+	return reflect.ValueOf(canonicalPlan{jplan})
+}
+
+// randomDungeon returns a random dungeon, whose goal is reachable through an L-shaped
+// corridor.
+func randomDungeon(r *rand.Rand, entrance xy.XY) JSONDungeon {
+	width, height := 2+r.Intn(6), 1+r.Intn(6)
+	rows := make([][]byte, height)
+	for y := range rows {
+		rows[y] = make([]byte, width)
+		for x := range rows[y] {
+			rows[y][x] = " #"[r.Intn(2)]
+		}
+	}
+
+	cells := r.Perm(width * height)[:2]
+	start := xy.XY{X: cells[0] % width, Y: cells[0] / width}
+	goal := xy.XY{X: cells[1] % width, Y: cells[1] / width}
+	for at := start; ; {
+		rows[at.Y][at.X] = ' '
+		switch {
+		case at.X < goal.X:
+			at.X++
+		case at.X > goal.X:
+			at.X--
+		case at.Y < goal.Y:
+			at.Y++
+		case at.Y > goal.Y:
+			at.Y--
+		}
+		if at == goal {
+			rows[at.Y][at.X] = ' '
+			break
+		}
+	}
+
+	dungeon := JSONDungeon{Entrance: entrance, Start: start, Goal: goal}
+	for _, row := range rows {
+		dungeon.Grid = append(dungeon.Grid, string(row))
+	}
+	return dungeon
+}
+
+func TestToJSONPlanRoundTrip(t *testing.T) {
+	jplan, err := DefaultJSONPlan().ToPlan().ToJSONPlan()
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if !reflect.DeepEqual(jplan, DefaultJSONPlan()) {
+		t.Error("Default plan differs once converted back")
+	}
+
+	roundTrip := func(cp canonicalPlan) bool {
+		if err := cp.Validate(); err != nil {
+			t.Log("Generated an invalid plan:", err)
+			return false
+		}
+		jplan, err := cp.ToPlan().ToJSONPlan()
+		if err != nil {
+			t.Log("Unexpected error:", err)
+			return false
+		}
+		return jplan.Validate() == nil && reflect.DeepEqual(jplan, cp.JSONPlan)
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMarshalJSONRoundTrip(t *testing.T) {
+	roundTrip := func(cp canonicalPlan) bool {
+		data, err := json.Marshal(cp.ToPlan())
+		if err != nil {
+			t.Log("Unexpected error:", err)
+			return false
+		}
+		var jplan JSONPlan
+		if err := json.Unmarshal(data, &jplan); err != nil {
+			t.Log("Unexpected error:", err)
+			return false
+		}
+		return reflect.DeepEqual(jplan.ToPlan(), cp.ToPlan())
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	jplan := DefaultJSONPlan()
+	data, err := FormatJSON(jplan)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	again, err := FormatJSON(jplan)
+	if err != nil || string(again) != string(data) {
+		t.Fatal("Expected formatting to be stable")
+	}
+
+	var decoded JSONPlan
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if !reflect.DeepEqual(decoded, jplan) {
+		t.Error("Decoded plan differs from the formatted one")
+	}
+	if string(data) != string(defaultJSONPlanData) {
+		t.Error("Expected the default plan to be laid out as default_plan.json")
+	}
+}
+
+func TestToJSONPlanErrors(t *testing.T) {
+	other := Forest
+	other.cost = 1
+	_, err := Plan{Grid: [][]Terrain{{Forest, other}}}.ToJSONPlan()
+	if !errors.Is(err, ErrDuplicateChar) {
+		t.Errorf("Expected ErrDuplicateChar, got %v", err)
+	}
+	_, err = Plan{Grid: [][]Terrain{{{}}}}.ToJSONPlan()
+	if !errors.Is(err, ErrInvalidTerrain) {
+		t.Errorf("Expected ErrInvalidTerrain, got %v", err)
+	}
+}
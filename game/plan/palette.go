@@ -0,0 +1,142 @@
+package plan
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// JSONTerrain represents a terrain of a plan's palette in a JSON format. A terrain is
+// drawn either with the image found at Image or as a block filled with Color, so exactly
+// one of them must be set.
+type JSONTerrain struct {
+	// Char is the single ASCII character which represents the terrain in the main map.
+	Char string `json:"char"`
+	Name string `json:"name"`
+	Cost int    `json:"cost"`
+	// Passable defaults to true when omitted.
+	Passable *bool `json:"passable,omitempty"`
+	// Direction makes the terrain one-way, so it may only be entered while moving in
+	// that direction. It is one of "north", "east", "south" and "west".
+	Direction string `json:"direction,omitempty"`
+
+	// Image is the path to a TIS by TIS PNG image. Relative paths are relative to the
+	// plan's Dir.
+	Image string `json:"image,omitempty"`
+	// Color is a color in the "#rrggbb" format.
+	Color string `json:"color,omitempty"`
+}
+
+// validate validates the terrain, which is found at path. Image paths are relative to
+// dir.
+func (jt JSONTerrain) validate(v *validator, path, dir string) {
+	invalid := func(field string, format string, args ...any) {
+		err := fmt.Errorf("%w: "+format, append([]any{ErrInvalidTerrain}, args...)...)
+		v.add(path+field, -1, -1, err)
+	}
+
+	if len(jt.Char) != 1 || jt.Char[0] < ' ' || jt.Char[0] > '~' {
+		invalid(".char", "character is not a single ASCII character: %q", jt.Char)
+	}
+	if jt.Name == "" {
+		invalid(".name", "terrain has no name")
+	}
+	if jt.Cost < 0 {
+		invalid(".cost", "terrain has negative cost: %d", jt.Cost)
+	}
+
+	if _, ok := parseDirection(jt.Direction); jt.Direction != "" && !ok {
+		invalid(".direction", "unknown direction: %q", jt.Direction)
+	}
+
+	switch {
+	case jt.Image != "" && jt.Color != "":
+		invalid("", "terrain has both an image and a color")
+	case jt.Image != "":
+		if err := checkImage(jt.imagePath(dir)); err != nil {
+			invalid(".image", "%v", err)
+		}
+	case jt.Color != "":
+		if _, err := parseColor(jt.Color); err != nil {
+			invalid(".color", "%v", err)
+		}
+	default:
+		invalid("", "terrain has neither an image nor a color")
+	}
+}
+
+// toTerrain converts the JSON terrain, which must be valid, into a Terrain.
+func (jt JSONTerrain) toTerrain(dir string) Terrain {
+	terrain := Terrain{
+		name: jt.Name, char: rune(jt.Char[0]),
+		cost: jt.Cost, passable: jt.passable(),
+	}
+	if direction, ok := parseDirection(jt.Direction); ok {
+		terrain.direction = direction.Offset()
+	}
+
+	if jt.Image != "" {
+		terrain.image = jt.imagePath(dir)
+	} else {
+		terrain.fill, _ = parseColor(jt.Color)
+	}
+
+	return terrain
+}
+
+func (jt JSONTerrain) passable() bool {
+	return jt.Passable == nil || *jt.Passable
+}
+
+func (jt JSONTerrain) imagePath(dir string) string {
+	if filepath.IsAbs(jt.Image) {
+		return jt.Image
+	}
+	return filepath.Join(dir, jt.Image)
+}
+
+// checkImage checks whether the file at path is a TIS by TIS image, without decoding it
+// entirely.
+func checkImage(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	if config.Width != TIS || config.Height != TIS {
+		return fmt.Errorf("%s is %dx%d, expected %dx%d", path, config.Width, config.Height, TIS, TIS)
+	}
+	return nil
+}
+
+// parseDirection parses the name of a direction which is not diagonal.
+func parseDirection(name string) (xy.Direction, bool) {
+	for _, direction := range xy.Directions4 {
+		if direction.String() == name {
+			return direction, true
+		}
+	}
+	return 0, false
+}
+
+// parseColor parses a color in the "#rrggbb" format.
+func parseColor(str string) (color.RGBA, error) {
+	var r, g, b uint8
+	if len(str) != len("#rrggbb") {
+		return color.RGBA{}, fmt.Errorf("%q is not in the #rrggbb format", str)
+	}
+	if _, err := fmt.Sscanf(str, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.RGBA{}, fmt.Errorf("%q is not in the #rrggbb format", str)
+	}
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}, nil
+}
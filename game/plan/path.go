@@ -0,0 +1,155 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// Tile is a terrain which may be placed on a grid and traversed by a path.
+type Tile interface {
+	Cost() int
+	Traversable() bool
+}
+
+var (
+	_ Tile = Terrain{}
+	_ Tile = Traversable
+)
+
+// Errors reported by ValidatePath. Errors regarding a single step are wrapped by a
+// PathError, so they may be checked through errors.Is.
+var (
+	ErrEmptyPath      = errors.New("path is empty")
+	ErrOutOfBounds    = errors.New("step is out of the grid's bounds")
+	ErrNotTraversable = errors.New("step is on a non traversable terrain")
+	ErrDiagonal       = errors.New("step is diagonal to the previous one")
+	ErrNotAdjacent    = errors.New("step is not adjacent to the previous one")
+	ErrCostMismatch   = errors.New("path cost differs from the claimed cost")
+	ErrWrongWay       = errors.New("step enters a one-way block against its direction")
+	ErrLocked         = errors.New("step enters a door whose key is not held")
+)
+
+// PathError is an error regarding a single step of a path.
+type PathError struct {
+	// Index is the offending step's index within the path.
+	Index int
+	// Step is the offending step.
+	Step xy.XY
+	Err  error
+}
+
+func (pe *PathError) Error() string {
+	return fmt.Sprintf("step (index %d) at (%d, %d) is invalid: %v", pe.Index, pe.Step.X, pe.Step.Y, pe.Err)
+}
+
+func (pe *PathError) Unwrap() error {
+	return pe.Err
+}
+
+// ValidatePath checks whether path is a valid path on grid and whether its cost is
+// cost. A valid path consists of traversable steps within the grid, each of them one
+// block away from the previous one in a non diagonal direction. The path's cost is the
+// sum of the costs of all of its steps except for the first one, as that is where the
+// path starts.
+//
+// The returned error is a *PathError for an invalid step, or it wraps ErrEmptyPath or
+// ErrCostMismatch.
+func ValidatePath[T Tile](grid [][]T, path []xy.XY, cost int) error {
+	return validatePath(grid, path, cost, adjacent)
+}
+
+// validatePath is ValidatePath, except that each step between two traversable blocks of
+// the grid is checked by move rather than by adjacent alone.
+func validatePath[T Tile](
+	grid [][]T, path []xy.XY, cost int, move func(from, to xy.XY) error,
+) error {
+	if len(path) == 0 {
+		return ErrEmptyPath
+	}
+
+	actualCost := 0
+	for idx, step := range path {
+		if step.Y < 0 || step.Y >= len(grid) || step.X < 0 || step.X >= len(grid[step.Y]) {
+			return &PathError{Index: idx, Step: step, Err: ErrOutOfBounds}
+		}
+		tile := grid[step.Y][step.X]
+		if !tile.Traversable() {
+			return &PathError{Index: idx, Step: step, Err: ErrNotTraversable}
+		}
+		if idx == 0 {
+			continue
+		}
+
+		if err := move(path[idx-1], step); err != nil {
+			return &PathError{Index: idx, Step: step, Err: err}
+		}
+		actualCost += tile.Cost()
+	}
+
+	if actualCost != cost {
+		return fmt.Errorf("%w: claimed %d, actual %d", ErrCostMismatch, cost, actualCost)
+	}
+	return nil
+}
+
+// adjacent checks whether a step leads one block away in a non diagonal direction.
+func adjacent(from, to xy.XY) error {
+	if xy.Manhattan(from, to) == 2 && xy.Chebyshev(from, to) == 1 {
+		return ErrDiagonal
+	}
+	if xy.Manhattan(from, to) != 1 {
+		return ErrNotAdjacent
+	}
+	return nil
+}
+
+// ValidatePath checks whether path is a valid path on the main map. See the package's
+// ValidatePath function for details. A step may also hop between the ends of one of the
+// plan's teleporters, in its direction if it is one-way, and may only enter a one-way
+// terrain while moving in its direction. The error of a step against a one-way terrain
+// wraps ErrWrongWay.
+func (p Plan) ValidatePath(path []xy.XY, cost int) error {
+	return validatePath(p.Grid, path, cost, func(from, to xy.XY) error {
+		for _, teleporter := range p.Teleporters {
+			if (from == teleporter.From && to == teleporter.To) ||
+				(!teleporter.OneWay && from == teleporter.To && to == teleporter.From) {
+				return nil
+			}
+		}
+		if err := adjacent(from, to); err != nil {
+			return err
+		}
+		if !p.Grid[to.Y][to.X].Enterable(to.Sub(from)) {
+			return ErrWrongWay
+		}
+		return nil
+	})
+}
+
+// ValidatePath checks whether path is a valid path within the dungeon. See the package's
+// ValidatePath function for details. Keys are picked up along the path, as in a
+// DungeonState, and a step may only enter a door whose key is held and a ledge while
+// moving in its direction. Such errors wrap ErrLocked and ErrWrongWay.
+func (d Dungeon) ValidatePath(path []xy.XY, cost int) error {
+	var keys Keys
+	return validatePath(d.Grid, path, cost, func(from, to xy.XY) error {
+		// every block up to from has been checked, so its key is picked up
+		if key, isKey := d.Grid[from.Y][from.X].Key(); isKey {
+			keys = keys.With(key)
+		}
+		if err := adjacent(from, to); err != nil {
+			return err
+		}
+
+		terrain := d.Grid[to.Y][to.X]
+		if key, isDoor := terrain.Door(); isDoor && !keys.Has(key) {
+			return ErrLocked
+		}
+		if direction, isLedge := terrain.Ledge(); isLedge && to.Sub(from) != direction {
+			return ErrWrongWay
+		}
+		return nil
+	})
+}
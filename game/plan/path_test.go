@@ -0,0 +1,135 @@
+package plan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestValidatePath(t *testing.T) {
+	grid := [][]DungeonTerrain{
+		{Traversable, Traversable, NonTraversable},
+		{NonTraversable, Traversable, Traversable},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		path := []xy.XY{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}}
+		if err := ValidatePath(grid, path, 30); err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+	})
+
+	t.Run("cost", func(t *testing.T) {
+		path := []xy.XY{{X: 0, Y: 0}, {X: 1, Y: 0}}
+		if err := ValidatePath(grid, path, 20); !errors.Is(err, ErrCostMismatch) {
+			t.Fatal("Expected cost mismatch, got", err)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if err := ValidatePath(grid, nil, 0); !errors.Is(err, ErrEmptyPath) {
+			t.Fatal("Expected empty path error, got", err)
+		}
+	})
+
+	for _, test := range []struct {
+		name  string
+		path  []xy.XY
+		index int
+		err   error
+	}{
+		{"bounds", []xy.XY{{X: 0, Y: 0}, {X: 0, Y: -1}}, 1, ErrOutOfBounds},
+		{"traversable", []xy.XY{{X: 1, Y: 0}, {X: 2, Y: 0}}, 1, ErrNotTraversable},
+		{"diagonal", []xy.XY{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 1}}, 2, ErrDiagonal},
+		{"adjacent", []xy.XY{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 0}}, 2, ErrNotAdjacent},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidatePath(grid, test.path, 0)
+
+			var pathErr *PathError
+			if !errors.As(err, &pathErr) {
+				t.Fatal("Expected a PathError, got", err)
+			}
+			if pathErr.Index != test.index {
+				t.Fatalf("Expected error at index %d, got %d", test.index, pathErr.Index)
+			}
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Expected '%v', got '%v'", test.err, pathErr.Err)
+			}
+		})
+	}
+}
+
+func TestPlanValidatePath(t *testing.T) {
+	jplan := JSONPlan{
+		Palette: []JSONTerrain{
+			{Char: "v", Name: "waterfall", Cost: 30, Direction: "south", Color: "#5080e0"},
+		},
+		MainMap: []string{"  ", " v", "  "},
+		Teleporters: []JSONTeleporter{
+			{From: xy.XY{X: 0, Y: 2}, To: xy.XY{X: 0, Y: 0}, OneWay: true},
+		},
+	}
+	gamePlan := jplan.ToPlan()
+
+	valid := map[string][]xy.XY{
+		"warp":      {{X: 0, Y: 2}, {X: 0, Y: 0}},
+		"waterfall": {{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+	}
+	for name, path := range valid {
+		cost := 0
+		for _, step := range path[1:] {
+			cost += gamePlan.Grid[step.Y][step.X].Cost()
+		}
+		if err := gamePlan.ValidatePath(path, cost); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+
+	invalid := map[string]struct {
+		path []xy.XY
+		err  error
+	}{
+		"warp back":      {[]xy.XY{{X: 0, Y: 0}, {X: 0, Y: 2}}, ErrNotAdjacent},
+		"up waterfall":   {[]xy.XY{{X: 1, Y: 2}, {X: 1, Y: 1}}, ErrWrongWay},
+		"waterfall side": {[]xy.XY{{X: 0, Y: 1}, {X: 1, Y: 1}}, ErrWrongWay},
+	}
+	for name, test := range invalid {
+		var pathErr *PathError
+		err := gamePlan.ValidatePath(test.path, 10)
+		if !errors.As(err, &pathErr) || pathErr.Index != 1 || !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v at index 1, got %v", name, test.err, err)
+		}
+	}
+}
+
+func TestDungeonValidatePath(t *testing.T) {
+	dungeon := JSONDungeon{Grid: []string{"a A", " > "}}.toDungeon()
+
+	keyFirst := []xy.XY{{X: 1, Y: 0}, {X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}
+	if err := dungeon.ValidatePath(keyFirst, 30); err != nil {
+		t.Error("Unexpected error:", err)
+	}
+	alongLedge := []xy.XY{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}}
+	if err := dungeon.ValidatePath(alongLedge, 20); err != nil {
+		t.Error("Unexpected error:", err)
+	}
+
+	invalid := map[string]struct {
+		path  []xy.XY
+		index int
+		err   error
+	}{
+		"locked door":   {[]xy.XY{{X: 1, Y: 0}, {X: 2, Y: 0}}, 1, ErrLocked},
+		"against ledge": {[]xy.XY{{X: 2, Y: 1}, {X: 1, Y: 1}}, 1, ErrWrongWay},
+		"onto ledge":    {[]xy.XY{{X: 1, Y: 0}, {X: 1, Y: 1}}, 1, ErrWrongWay},
+	}
+	for name, test := range invalid {
+		var pathErr *PathError
+		err := dungeon.ValidatePath(test.path, 0)
+		if !errors.As(err, &pathErr) || pathErr.Index != test.index || !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v at index %d, got %v", name, test.err, test.index, err)
+		}
+	}
+}
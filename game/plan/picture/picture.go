@@ -0,0 +1,247 @@
+// Package picture imports plans painted in an image editor. Each pixel of a PNG image
+// stands for a cell of the main map, and its color is mapped to a terrain through a
+// palette.
+//
+// A few colors are markers rather than terrains: they place the start, the gate, the
+// sword and the dungeon entrances. As marked cells still need a terrain, they are given
+// the palette's Ground terrain.
+//
+// Dungeons are painted in separate images, which only use two colors, for walls and
+// floors, besides the markers of their start and goal. The image of the dungeon whose
+// entrance comes first, in reading order, is named after the map's image with a
+// ".dungeon1" suffix ("overworld.dungeon1.png"), the second with a ".dungeon2" suffix,
+// and so on.
+package picture
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// Palette maps the colors of an image to terrains and markers. Pixels are compared by
+// their red, green and blue components only, so the palette's colors must be opaque.
+type Palette struct {
+	// Terrains maps colors to the characters of terrains, which are either predefined or
+	// declared in Custom.
+	Terrains map[color.RGBA]byte
+	// Custom holds the terrains which are not predefined. It becomes the plan's palette.
+	Custom []plan.JSONTerrain
+	// Ground is the character of the terrain of the cells holding markers.
+	Ground byte
+
+	Start, Gate, Sword, Entrance color.RGBA
+
+	// Wall and Floor are the colors of dungeon images, and Goal marks a dungeon's goal.
+	// A dungeon's start is marked with the Start color.
+	Wall, Floor, Goal color.RGBA
+}
+
+// DefaultPalette returns a palette of the predefined terrains, whose markers are pure
+// colors.
+func DefaultPalette() Palette {
+	return Palette{
+		Terrains: map[color.RGBA]byte{
+			{R: 0x70, G: 0xb0, B: 0x40, A: 0xff}: byte(plan.Grass.Char()),
+			{R: 0x20, G: 0x60, B: 0x20, A: 0xff}: byte(plan.Forest.Char()),
+			{R: 0x80, G: 0x60, B: 0x40, A: 0xff}: byte(plan.Mountain.Char()),
+			{R: 0xe0, G: 0xd0, B: 0x90, A: 0xff}: byte(plan.Sand.Char()),
+			{R: 0x30, G: 0x60, B: 0xc0, A: 0xff}: byte(plan.Water.Char()),
+		},
+		Ground: byte(plan.Grass.Char()),
+
+		Start:    color.RGBA{R: 0xff, A: 0xff},
+		Gate:     color.RGBA{R: 0xff, B: 0xff, A: 0xff},
+		Sword:    color.RGBA{R: 0xff, G: 0xff, A: 0xff},
+		Entrance: color.RGBA{G: 0xff, B: 0xff, A: 0xff},
+
+		Wall:  color.RGBA{A: 0xff},
+		Floor: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		Goal:  color.RGBA{R: 0xff, G: 0xff, A: 0xff},
+	}
+}
+
+// Load reads the image at path, along with its dungeons' images, and converts them to a
+// plan, which is validated.
+func Load(path string, palette Palette) (plan.Plan, error) {
+	jplan, err := LoadJSONPlan(path, palette)
+	if err != nil {
+		return plan.Plan{}, err
+	}
+	if err := jplan.Validate(); err != nil {
+		return plan.Plan{}, fmt.Errorf("invalid plan: %w", err)
+	}
+	return jplan.ToPlan(), nil
+}
+
+// LoadJSONPlan reads the image at path, along with its dungeons' images, and converts
+// them to a JSON plan, which is not validated.
+func LoadJSONPlan(path string, palette Palette) (plan.JSONPlan, error) {
+	img, err := readImage(path)
+	if err != nil {
+		return plan.JSONPlan{}, err
+	}
+	jplan, err := Decode(img, palette)
+	if err != nil {
+		return plan.JSONPlan{}, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	jplan.Dir = filepath.Dir(path)
+
+	for idx := range jplan.Dungeons {
+		dungeonPath := DungeonPath(path, idx)
+		img, err := readImage(dungeonPath)
+		if err != nil {
+			return plan.JSONPlan{}, err
+		}
+		dungeon, err := DecodeDungeon(img, palette)
+		if err != nil {
+			return plan.JSONPlan{}, fmt.Errorf("failed to decode %s: %w", dungeonPath, err)
+		}
+		dungeon.Entrance = jplan.Dungeons[idx].Entrance
+		jplan.Dungeons[idx] = dungeon
+	}
+	return jplan, nil
+}
+
+// DungeonPath returns the path of the image of the dungeon of index idx, whose map's
+// image is at path.
+func DungeonPath(path string, idx int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.dungeon%d%s", strings.TrimSuffix(path, ext), idx+1, ext)
+}
+
+// Decode converts img to a JSON plan. Its dungeons only have their entrances set, in
+// reading order.
+func Decode(img image.Image, palette Palette) (plan.JSONPlan, error) {
+	jplan := plan.JSONPlan{Version: plan.CurrentVersion, Palette: palette.Custom}
+	markers := make(map[color.RGBA]int)
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		var row strings.Builder
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			at := xy.XY{X: x - bounds.Min.X, Y: y - bounds.Min.Y}
+			c := rgb(img.At(x, y))
+
+			switch c {
+			case palette.Start:
+				jplan.Start = at
+			case palette.Gate:
+				jplan.LostWoods = at
+			case palette.Sword:
+				jplan.MasterSword = at
+			case palette.Entrance:
+				jplan.Dungeons = append(jplan.Dungeons, plan.JSONDungeon{Entrance: at})
+			default:
+				char, ok := palette.Terrains[c]
+				if !ok {
+					return plan.JSONPlan{}, unknownColor(at, c)
+				}
+				row.WriteByte(char)
+				continue
+			}
+			markers[c]++
+			row.WriteByte(palette.Ground)
+		}
+		jplan.MainMap = append(jplan.MainMap, row.String())
+	}
+
+	err := checkMarkers(markers, map[string]color.RGBA{
+		"start": palette.Start, "gate": palette.Gate, "sword": palette.Sword,
+	})
+	if err != nil {
+		return plan.JSONPlan{}, err
+	}
+	return jplan, nil
+}
+
+// DecodeDungeon converts img to a dungeon, whose entrance is left unset.
+func DecodeDungeon(img image.Image, palette Palette) (plan.JSONDungeon, error) {
+	var dungeon plan.JSONDungeon
+	markers := make(map[color.RGBA]int)
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		var row strings.Builder
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			at := xy.XY{X: x - bounds.Min.X, Y: y - bounds.Min.Y}
+			c := rgb(img.At(x, y))
+
+			switch c {
+			case palette.Wall:
+				row.WriteByte('#')
+				continue
+			case palette.Floor:
+			case palette.Start:
+				dungeon.Start = at
+				markers[c]++
+			case palette.Goal:
+				dungeon.Goal = at
+				markers[c]++
+			default:
+				return dungeon, unknownColor(at, c)
+			}
+			row.WriteByte(' ')
+		}
+		dungeon.Grid = append(dungeon.Grid, row.String())
+	}
+
+	err := checkMarkers(markers, map[string]color.RGBA{
+		"start": palette.Start, "goal": palette.Goal,
+	})
+	return dungeon, err
+}
+
+// checkMarkers checks that each of the required markers, which are indexed by their
+// names, was found exactly once. counts holds the amount of times each color was found.
+func checkMarkers(counts map[color.RGBA]int, required map[string]color.RGBA) error {
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c := required[name]
+		if counts[c] != 1 {
+			return fmt.Errorf("image has %d %s markers (%s), expected 1", counts[c], name, hex(c))
+		}
+	}
+	return nil
+}
+
+func unknownColor(at xy.XY, c color.RGBA) error {
+	return fmt.Errorf("pixel at (%d, %d) has unknown color %s", at.X, at.Y, hex(c))
+}
+
+func readImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// rgb returns the opaque color of c, ignoring its alpha.
+func rgb(c color.Color) color.RGBA {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return color.RGBA{R: nrgba.R, G: nrgba.G, B: nrgba.B, A: 0xff}
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
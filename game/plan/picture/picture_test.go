@@ -0,0 +1,143 @@
+package picture
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+var road = color.RGBA{R: 0xa0, G: 0x80, B: 0x60, A: 0xff}
+
+// paint returns an image whose pixels are painted with the colors of the characters of
+// rows: '@' is forest, ' ' is grass, '=' is road, 'S', 'L', 'M' and 'E' are the start,
+// gate, sword and entrance markers, '#' and '.' are walls and floors, and 'G' is a
+// dungeon's goal.
+func paint(rows ...string) *image.NRGBA {
+	palette := testPalette()
+	colors := map[byte]color.RGBA{
+		'=': road, 'S': palette.Start, 'L': palette.Gate, 'M': palette.Sword,
+		'E': palette.Entrance, '#': palette.Wall, '.': palette.Floor, 'G': palette.Goal,
+	}
+	for c, char := range palette.Terrains {
+		if char != '=' {
+			colors[char] = c
+		}
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x := range row {
+			img.Set(x, y, colors[row[x]])
+		}
+	}
+	return img
+}
+
+func testPalette() Palette {
+	palette := DefaultPalette()
+	palette.Terrains[road] = '='
+	palette.Custom = []plan.JSONTerrain{{Char: "=", Name: "road", Cost: 5, Color: "#a08060"}}
+	return palette
+}
+
+func writePNG(t *testing.T, path string, img image.Image) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadJSONPlan(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "overworld.png")
+	writePNG(t, path, paint("@@@@@@", "@S =L@", "@M  E@", "@@@@@@"))
+	writePNG(t, filepath.Join(dir, "overworld.dungeon1.png"), paint("####", "#S.#", "##G#"))
+
+	jplan, err := LoadJSONPlan(path, testPalette())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	want := plan.JSONPlan{
+		Version:     plan.CurrentVersion,
+		MasterSword: xy.XY{X: 1, Y: 2},
+		LostWoods:   xy.XY{X: 4, Y: 1},
+		Start:       xy.XY{X: 1, Y: 1},
+		Palette:     testPalette().Custom,
+		MainMap:     []string{"@@@@@@", "@  = @", "@    @", "@@@@@@"},
+		Dungeons: []plan.JSONDungeon{{
+			Grid:     []string{"####", "#  #", "## #"},
+			Entrance: xy.XY{X: 4, Y: 2},
+			Start:    xy.XY{X: 1, Y: 1},
+			Goal:     xy.XY{X: 2, Y: 2},
+		}},
+		Dir: dir,
+	}
+	if !reflect.DeepEqual(jplan, want) {
+		t.Errorf("Unexpected plan: %+v", jplan)
+	}
+
+	gamePlan, err := Load(path, testPalette())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if terrain := gamePlan.Grid[1][3]; terrain.Name() != "road" || terrain.Cost() != 5 {
+		t.Errorf("Unexpected road terrain: %s costing %d", terrain.Name(), terrain.Cost())
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	cases := map[string]*image.NRGBA{
+		"unknown color":      image.NewNRGBA(image.Rect(0, 0, 2, 2)),
+		"0 start markers":    paint("@L", "M "),
+		"2 start markers":    paint("SL", "MS"),
+		"0 sword markers":    paint("SL", "  "),
+		"pixel at (1, 0)":    paint("S=", "LM"),
+		"2 gate markers":     paint("SL", "ML"),
+		"has 0 gate markers": paint("S ", "M "),
+	}
+	palette := DefaultPalette()
+	for want, img := range cases {
+		_, err := Decode(img, palette)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error containing %q, got %v", want, err)
+		}
+	}
+
+	dungeonCases := map[string]*image.NRGBA{
+		"0 goal markers":  paint("#S", "#."),
+		"2 start markers": paint("SS", "G."),
+		"pixel at (0, 1)": paint("SG", "@."),
+		"0 start markers": paint("..", "G#"),
+	}
+	for want, img := range dungeonCases {
+		_, err := DecodeDungeon(img, palette)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestMissingDungeon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overworld.png")
+	writePNG(t, path, paint("SLME"))
+
+	_, err := LoadJSONPlan(path, DefaultPalette())
+	if err == nil || !strings.Contains(err.Error(), "failed to read image") {
+		t.Errorf("Expected the missing dungeon image to be reported, got %v", err)
+	}
+	if got := DungeonPath(path, 0); filepath.Base(got) != "overworld.dungeon1.png" {
+		t.Errorf("Unexpected dungeon path: %s", got)
+	}
+}
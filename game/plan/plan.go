@@ -2,38 +2,40 @@
 // "Plan" is used as synonym to map, as "map" is a reserved word.
 package plan
 
-import (
-	"github.com/agstrc/heuristic-search/xy"
-	"github.com/hajimehoshi/ebiten/v2"
-)
-
-const (
-	// DungeonCount is the number of dungeons in the game's map.
-	DungeonCount = 3
-	// The main map size in number of blocks.
-	MainMapSize = 42
-	// The dungeon size in number of blocks.
-	DungeonSize = 28
-)
+import "github.com/agstrc/heuristic-search/xy"
 
 // Plan is the game's map. It consists of a "main" map, which is all areas outside of a
-// dungeon, and three dungeons, each with its own inner map.
+// dungeon, and any number of dungeons, each with its own inner map. Every map is a
+// rectangular grid of any size.
 type Plan struct {
 	// Grid is the main map's terrain grid.
 	Grid [][]Terrain
 
+	// Start defines the agent's starting point, which the agent must return to once
+	// every dungeon's goal is collected.
+	Start xy.XY
 	// Sword defines the Master Sword's coordinates.
 	Sword xy.XY
 	// Gate defines the Lost Wood's gate coordinates.
 	Gate xy.XY
 
 	// Dungeons are all the dungeons in the game.
-	Dungeons [DungeonCount]Dungeon
+	Dungeons []Dungeon
+	// Teleporters are all the teleporters of the main map.
+	Teleporters []Teleporter
+}
+
+// Teleporter joins two blocks of the main map, so stepping from one onto the other costs
+// as much as stepping onto the other block from its neighbors.
+type Teleporter struct {
+	From, To xy.XY
+	// OneWay reports whether the teleporter is a warp, which only leads from From to To.
+	OneWay bool
 }
 
 // Dungeon contains all data required to represent a dungeon within the main map.
 type Dungeon struct {
-	// Grid is the dungeon's inner grid. It reports whether a position is traversable.
+	// Grid is the dungeon's inner grid. See DungeonTerrain for the terrains it may hold.
 	Grid [][]DungeonTerrain
 
 	// Entrance is the dungeon's entrance in the main map.
@@ -44,7 +46,4 @@ type Dungeon struct {
 	Start xy.XY
 	// GoalXY is the goal's position in the inner grid.
 	GoalXY xy.XY
-	// GoalImg is the image which will be used to visually represent the goal inside the
-	// dungeon.
-	GoalImg *ebiten.Image
 }
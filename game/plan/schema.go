@@ -0,0 +1,110 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SchemaID identifies the JSON Schema of plans, as returned by JSONSchema.
+const SchemaID = "https://github.com/agstrc/heuristic-search/game/plan/plan.schema.json"
+
+// schemaConstraints holds the constraints of fields which their Go types can't express,
+// indexed by the type and name of each field.
+var schemaConstraints = map[string]map[string]any{
+	"JSONPlan.Version":      {"const": CurrentVersion},
+	"JSONPlan.MainMap":      {"minItems": 1},
+	"JSONTerrain.Char":      {"pattern": "^[ -~]$"},
+	"JSONTerrain.Name":      {"minLength": 1},
+	"JSONTerrain.Cost":      {"minimum": 0},
+	"JSONTerrain.Color":     {"pattern": "^#[0-9a-fA-F]{6}$"},
+	"JSONTerrain.Direction": {"enum": []string{"north", "east", "south", "west"}},
+	"JSONDungeon.Grid":      {"minItems": 1},
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) of plans of CurrentVersion, so editors
+// may validate plan files as they are written. It is generated from the JSONPlan type,
+// and checked in as plan.schema.json.
+//
+// The schema checks the structure of a plan, but not its semantics, such as whether its
+// rows are as long as each other or its coordinates lie within its maps; Validate does.
+func JSONSchema() ([]byte, error) {
+	gen := schemaGenerator{defs: make(map[string]any)}
+	schema := gen.object(reflect.TypeOf(JSONPlan{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = SchemaID
+	schema["title"] = "Plan"
+	schema["$defs"] = gen.defs
+	// plan files may refer to the schema, which is how most editors find it
+	schema["properties"].(map[string]any)["$schema"] = map[string]any{"type": "string"}
+
+	data, err := json.MarshalIndent(schema, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaGenerator generates the schemas of Go types. Structs other than the root one
+// are defined once, under "$defs", and referred to wherever they are used.
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+func (gen schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return gen.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int:
+		return map[string]any{"type": "integer"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": gen.schema(t.Elem())}
+	case reflect.Struct:
+		if _, isDefined := gen.defs[t.Name()]; !isDefined {
+			// the definition is reserved first, so recursive types terminate
+			gen.defs[t.Name()] = nil
+			gen.defs[t.Name()] = gen.object(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	}
+	panic(fmt.Sprintf("no schema for type %s", t))
+}
+
+// object returns the schema of a struct, whose properties are its encoded fields.
+// Fields which are omitted when empty are optional.
+func (gen schemaGenerator) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for idx := 0; idx < t.NumField(); idx++ {
+		field := t.Field(idx)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		schema := gen.schema(field.Type)
+		for key, value := range schemaConstraints[t.Name()+"."+field.Name] {
+			schema[key] = value
+		}
+		properties[name] = schema
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
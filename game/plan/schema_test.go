@@ -0,0 +1,101 @@
+package plan
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the checked-in JSON Schema")
+
+func TestJSONSchemaIsUpToDate(t *testing.T) {
+	schema, err := JSONSchema()
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if *update {
+		if err := os.WriteFile("plan.schema.json", schema, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkedIn, err := os.ReadFile("plan.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(checkedIn) != string(schema) {
+		t.Error("plan.schema.json is out of date; run go test ./game/plan -run Schema -update")
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	var schema struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+		Defs       map[string]struct {
+			Required []string `json:"required"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	for _, property := range [...]string{"version", "main_map", "palette", "$schema"} {
+		if _, ok := schema.Properties[property]; !ok {
+			t.Errorf("Expected the %q property", property)
+		}
+	}
+	if len(schema.Required) != 5 {
+		t.Errorf("Unexpected required properties: %v", schema.Required)
+	}
+	if terrain := schema.Defs["JSONTerrain"]; len(terrain.Required) != 3 {
+		t.Errorf("Expected char, name and cost to be required, got %v", terrain.Required)
+	}
+	for _, def := range [...]string{"JSONDungeon", "XY"} {
+		if _, ok := schema.Defs[def]; !ok {
+			t.Errorf("Expected the %s definition", def)
+		}
+	}
+}
+
+func TestJSONSchemaAllowsPlanWithoutDungeons(t *testing.T) {
+	data := `{
+		"version": 3,
+		"master_sword": {"x": 0, "y": 0},
+		"lost_woods": {"x": 1, "y": 0},
+		"start": {"x": 2, "y": 0},
+		"main_map": ["   "]
+	}`
+	jplan, err := ParseJSON([]byte(data))
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if err := jplan.Validate(); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	schemaData, err := JSONSchema()
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	var schema struct {
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(schemaData, &schema); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	for _, property := range schema.Required {
+		if _, ok := doc[property]; !ok {
+			t.Errorf("Schema requires %q, which the parser does not", property)
+		}
+	}
+}
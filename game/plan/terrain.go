@@ -1,60 +1,191 @@
 package plan
 
 import (
-	"github.com/agstrc/heuristic-search/images"
-	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+
+	"github.com/agstrc/heuristic-search/xy"
 )
 
 // TIS (terrain image size) is the expected length and width for every terrain image.
 const TIS = 32
 
-// Terrain is a type of terrain which may be traversed in the main map. Each terrain has
-// an image and a traversal cost.
+// Terrain is a type of terrain which may be placed in the main map. Each terrain has a
+// name, the character which represents it in a JSON plan and a traversal cost. A terrain
+// declared by a palette also defines how it is drawn, either through an image file or a
+// color; predefined terrains are drawn according to their names.
 type Terrain struct {
-	cost  int
-	image *ebiten.Image
+	name     string
+	char     rune
+	cost     int
+	passable bool
+	// direction is the offset of the only move which may enter a one-way terrain.
+	direction xy.XY
+
+	image string
+	fill  color.RGBA
+}
+
+// Name returns the terrain's name, such as "forest", which identifies it when drawn.
+func (t Terrain) Name() string {
+	return t.name
 }
 
+// Char returns the character which represents the terrain in a JSON plan.
+func (t Terrain) Char() rune {
+	return t.char
+}
+
+// Cost returns the cost of stepping onto the terrain.
 func (t Terrain) Cost() int {
 	return t.cost
 }
 
-func (t Terrain) Image() *ebiten.Image {
+// Traversable reports whether the terrain may be traversed. Every predefined terrain is
+// traversable, but a palette may declare terrains which are not.
+func (t Terrain) Traversable() bool {
+	return t.passable
+}
+
+// Direction returns the direction of a one-way terrain, such as a waterfall, as the
+// offset of a single step in that direction. A one-way terrain may only be entered while
+// moving in its direction. The returned bool is false unless the terrain is one-way,
+// which only terrains declared by a palette may be.
+func (t Terrain) Direction() (xy.XY, bool) {
+	return t.direction, t.direction != xy.XY{}
+}
+
+// Enterable reports whether the terrain may be entered by a move of the given offset,
+// which is always the case unless it is one-way.
+func (t Terrain) Enterable(step xy.XY) bool {
+	direction, isOneWay := t.Direction()
+	return !isOneWay || step == direction
+}
+
+// Image returns the path to the terrain's image file. It is empty unless the terrain was
+// declared by a palette with an image.
+func (t Terrain) Image() string {
 	return t.image
 }
 
-// Predefined terrain types.
+// Color returns the color which the terrain is filled with. The returned bool is false
+// unless the terrain was declared by a palette with a color.
+func (t Terrain) Color() (color.RGBA, bool) {
+	return t.fill, t.fill != color.RGBA{}
+}
+
+// Predefined terrain types, which form the default palette. Grass is the cheapest one.
 var (
-	Forest   = Terrain{cost: 100, image: images.Forest}
-	Grass    = Terrain{cost: 10, image: images.Grass}
-	Mountain = Terrain{cost: 150, image: images.Mountain}
-	Sand     = Terrain{cost: 20, image: images.Sand}
-	Water    = Terrain{cost: 180, image: images.Water}
+	Forest   = Terrain{name: "forest", char: '@', cost: 100, passable: true}
+	Grass    = Terrain{name: "grass", char: ' ', cost: 10, passable: true}
+	Mountain = Terrain{name: "mountain", char: '%', cost: 150, passable: true}
+	Sand     = Terrain{name: "sand", char: '_', cost: 20, passable: true}
+	Water    = Terrain{name: "water", char: '*', cost: 180, passable: true}
 )
 
-// DungeonTerrain is a terrain found in a dungeon. As dungeons are composed of either
-// traversable or non traversable terrains, it may only be one of two values. Its inner
-// type is a bool, which defines wheter its a traversable or non traversable terrain.
-type DungeonTerrain bool
+// DefaultPalette returns the predefined terrain types.
+func DefaultPalette() []Terrain {
+	return []Terrain{Forest, Grass, Mountain, Sand, Water}
+}
+
+// MinCost returns the cost of the cheapest traversable tile of grid, which is the scale
+// that keeps a distance heuristic from overestimating the cost of a path. If no tile is
+// traversable, zero is returned.
+func MinCost[T Tile](grid [][]T) int {
+	minCost, found := 0, false
+	for _, row := range grid {
+		for _, tile := range row {
+			if tile.Traversable() && (!found || tile.Cost() < minCost) {
+				minCost, found = tile.Cost(), true
+			}
+		}
+	}
+	return minCost
+}
+
+// DungeonTerrain is a terrain found in a dungeon. Its inner type is the character which
+// represents it in a JSON dungeon's grid:
+//
+//   - '#' is a wall, which may never be traversed, and ' ' is a floor.
+//   - 'a' to 'j' are floors holding a key, which is picked up by stepping onto it.
+//   - 'A' to 'J' are doors, which may only be traversed while holding the key of the same
+//     letter. Keys are never used up, so a key opens every door of its letter.
+//   - '!' is a trap, which may be traversed at a higher cost.
+//   - '>', '<', 'v' and '^' are ledges, which may only be stepped onto while moving in the
+//     direction they point to. Leaving a ledge is not restricted.
+type DungeonTerrain byte
 
 const (
-	Traversable    DungeonTerrain = true
-	NonTraversable DungeonTerrain = false
+	Traversable    DungeonTerrain = ' '
+	NonTraversable DungeonTerrain = '#'
+	Trap           DungeonTerrain = '!'
 )
 
-// Cost returns the terrain's cost. It is a constant value and only valid for traversable
-// terrains. If called on a non traversable terrain, Cost panics.
+// MaxKeys is the amount of different keys, and thus of different doors, a dungeon may
+// hold.
+const MaxKeys = 10
+
+// Key returns the terrain holding the key at index, which must be lower than MaxKeys.
+func Key(index int) DungeonTerrain {
+	return DungeonTerrain('a' + index)
+}
+
+// Door returns the terrain of the door opened by the key at index, which must be lower
+// than MaxKeys.
+func Door(index int) DungeonTerrain {
+	return DungeonTerrain('A' + index)
+}
+
+// Cost returns the terrain's cost, which is higher for traps than for every other
+// traversable terrain. If called on a non traversable terrain, Cost panics.
 func (dt DungeonTerrain) Cost() int {
-	if !dt {
+	if !dt.Traversable() {
 		panic("attempt to get cost of non traversable block")
 	}
+	if dt == Trap {
+		return 50
+	}
 	return 10
 }
 
-func (dt DungeonTerrain) Image() *ebiten.Image {
-	if dt {
-		return images.Traversable
-	} else {
-		return images.NonTraversable
+// Traversable reports whether the terrain may be traversed, which is the case for every
+// terrain except for walls. Doors and ledges are traversable, even though they may only
+// be traversed under some conditions.
+func (dt DungeonTerrain) Traversable() bool {
+	switch dt {
+	case Traversable, Trap, '>', '<', 'v', '^':
+		return true
+	}
+	_, isKey := dt.Key()
+	_, isDoor := dt.Door()
+	return isKey || isDoor
+}
+
+// Key returns the index of the key which the terrain holds. The returned bool is false
+// unless the terrain holds a key.
+func (dt DungeonTerrain) Key() (int, bool) {
+	index := int(dt) - 'a'
+	return index, index >= 0 && index < MaxKeys
+}
+
+// Door returns the index of the key which opens the terrain. The returned bool is false
+// unless the terrain is a door.
+func (dt DungeonTerrain) Door() (int, bool) {
+	index := int(dt) - 'A'
+	return index, index >= 0 && index < MaxKeys
+}
+
+// Ledge returns the direction the terrain points to, as the offset of a single step in
+// that direction. The returned bool is false unless the terrain is a ledge.
+func (dt DungeonTerrain) Ledge() (xy.XY, bool) {
+	switch dt {
+	case '>':
+		return xy.XY{X: 1}, true
+	case '<':
+		return xy.XY{X: -1}, true
+	case 'v':
+		return xy.XY{Y: 1}, true
+	case '^':
+		return xy.XY{Y: -1}, true
 	}
+	return xy.XY{}, false
 }
@@ -0,0 +1,508 @@
+package plan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// This file implements a plain-text plan format, which is easier to write by hand than
+// JSON. A plan is a sequence of directives, one per line, and blank lines and lines
+// starting with "//" are ignored. Coordinates are written as "x,y":
+//
+//	start 24,27
+//	lost_woods 6,5
+//	master_sword 2,1
+//	terrain "=" name=road cost=5 color=#a08060
+//	terrain "~" name="deep swamp" cost=60 passable=false image=tiles/swamp.png
+//	terrain "v" name=waterfall cost=30 direction=south color=#5080e0
+//
+//	map
+//	```
+//	@@@@@@@
+//	@  _  @
+//	```
+//
+//	dungeon entrance=3,1 start=1,1 goal=5,1
+//	```
+//	#######
+//	#  #  #
+//	```
+//	dungeon entrance=4,1 start=1,1 goal=2,1 file=dungeons/second.txt
+//
+//	teleporter from=1,1 to=5,1
+//	teleporter from=2,1 to=5,0 one_way=true
+//
+// The rows of a map are written between two fence lines ("```"). Rows shorter than the
+// block's longest row are filled with spaces, so trailing spaces may be omitted. A
+// dungeon may instead refer to a file which holds nothing but its rows. Teleporters lead
+// both ways, unless they are one-way warps.
+
+// TextError is an error found while parsing a plan in the plain-text format.
+type TextError struct {
+	// Line is the number of the offending line, starting at 1.
+	Line int
+	Err  error
+}
+
+func (te *TextError) Error() string {
+	return fmt.Sprintf("line %d: %v", te.Line, te.Err)
+}
+
+func (te *TextError) Unwrap() error {
+	return te.Err
+}
+
+const textFence = "```"
+
+// LoadText reads a plan in the plain-text format from the file at path.
+func LoadText(path string) (JSONPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return JSONPlan{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	return ParseText(data, filepath.Dir(path))
+}
+
+// ParseText parses a plan in the plain-text format. Dungeon files and palette images are
+// relative to dir, which becomes the plan's Dir. The format has no versions of its own,
+// so the plan is of CurrentVersion. The plan is not validated.
+func ParseText(data []byte, dir string) (JSONPlan, error) {
+	parser := textParser{
+		lines: strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"),
+		dir:   dir, seen: make(map[string]struct{}),
+	}
+	parser.plan.Version, parser.plan.Dir = CurrentVersion, dir
+	if err := parser.parse(); err != nil {
+		return JSONPlan{}, err
+	}
+	return parser.plan, nil
+}
+
+// textParser holds the state of a plain-text plan being parsed.
+type textParser struct {
+	lines []string
+	// next is the index of the next line to be parsed.
+	next int
+	dir  string
+
+	plan JSONPlan
+	// seen is the set of directives which may only be found once and have been parsed.
+	seen map[string]struct{}
+}
+
+func (tp *textParser) errorf(line int, format string, args ...any) error {
+	return &TextError{Line: line, Err: fmt.Errorf(format, args...)}
+}
+
+func (tp *textParser) parse() error {
+	for tp.next < len(tp.lines) {
+		line := tp.next + 1
+		content := strings.TrimSpace(tp.lines[tp.next])
+		tp.next++
+		if content == "" || strings.HasPrefix(content, "//") {
+			continue
+		}
+
+		tokens, err := textTokens(content)
+		if err != nil {
+			return tp.errorf(line, "%v", err)
+		}
+		if err := tp.directive(line, tokens[0], tokens[1:]); err != nil {
+			return err
+		}
+	}
+
+	for _, directive := range [...]string{"start", "lost_woods", "master_sword", "map"} {
+		if _, isSeen := tp.seen[directive]; !isSeen {
+			return tp.errorf(len(tp.lines), "missing %s directive", directive)
+		}
+	}
+	return nil
+}
+
+func (tp *textParser) directive(line int, name string, args []string) error {
+	switch name {
+	case "start", "lost_woods", "master_sword", "map":
+		if _, isSeen := tp.seen[name]; isSeen {
+			return tp.errorf(line, "duplicate %s directive", name)
+		}
+		tp.seen[name] = struct{}{}
+	}
+
+	switch name {
+	case "start", "lost_woods", "master_sword":
+		if len(args) != 1 {
+			return tp.errorf(line, "%s expects a single coordinate pair", name)
+		}
+		coord, err := parseCoordinate(args[0])
+		if err != nil {
+			return tp.errorf(line, "%v", err)
+		}
+		switch name {
+		case "start":
+			tp.plan.Start = coord
+		case "lost_woods":
+			tp.plan.LostWoods = coord
+		default:
+			tp.plan.MasterSword = coord
+		}
+
+	case "terrain":
+		terrain, err := parseTextTerrain(args)
+		if err != nil {
+			return tp.errorf(line, "%v", err)
+		}
+		tp.plan.Palette = append(tp.plan.Palette, terrain)
+
+	case "map":
+		if len(args) != 0 {
+			return tp.errorf(line, "map expects no arguments")
+		}
+		rows, err := tp.block(line)
+		if err != nil {
+			return err
+		}
+		tp.plan.MainMap = rows
+
+	case "dungeon":
+		return tp.dungeon(line, args)
+
+	case "teleporter":
+		teleporter, err := parseTextTeleporter(args)
+		if err != nil {
+			return tp.errorf(line, "%v", err)
+		}
+		tp.plan.Teleporters = append(tp.plan.Teleporters, teleporter)
+
+	default:
+		return tp.errorf(line, "unknown directive: %q", name)
+	}
+	return nil
+}
+
+func (tp *textParser) dungeon(line int, args []string) error {
+	var dungeon JSONDungeon
+	var file string
+	found := make(map[string]struct{})
+	for _, arg := range args {
+		key, value, err := textField(arg)
+		if err != nil {
+			return tp.errorf(line, "%v", err)
+		}
+		if _, isFound := found[key]; isFound {
+			return tp.errorf(line, "duplicate dungeon field: %s", key)
+		}
+		found[key] = struct{}{}
+
+		var coord *xy.XY
+		switch key {
+		case "entrance":
+			coord = &dungeon.Entrance
+		case "start":
+			coord = &dungeon.Start
+		case "goal":
+			coord = &dungeon.Goal
+		case "file":
+			file = value
+			continue
+		default:
+			return tp.errorf(line, "unknown dungeon field: %s", key)
+		}
+		if *coord, err = parseCoordinate(value); err != nil {
+			return tp.errorf(line, "%s: %v", key, err)
+		}
+	}
+	for _, key := range [...]string{"entrance", "start", "goal"} {
+		if _, isFound := found[key]; !isFound {
+			return tp.errorf(line, "missing dungeon field: %s", key)
+		}
+	}
+
+	if file == "" {
+		rows, err := tp.block(line)
+		if err != nil {
+			return err
+		}
+		dungeon.Grid = rows
+	} else {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(tp.dir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return tp.errorf(line, "failed to read dungeon file: %v", err)
+		}
+		dungeon.Grid = padRows(textRows(data))
+	}
+
+	tp.plan.Dungeons = append(tp.plan.Dungeons, dungeon)
+	return nil
+}
+
+// block parses the fenced block of rows which follows the directive at line.
+func (tp *textParser) block(line int) ([]string, error) {
+	for tp.next < len(tp.lines) && strings.TrimSpace(tp.lines[tp.next]) == "" {
+		tp.next++
+	}
+	if tp.next >= len(tp.lines) || !strings.HasPrefix(tp.lines[tp.next], textFence) {
+		return nil, tp.errorf(line, "expected a block of rows opened by %s", textFence)
+	}
+	tp.next++
+
+	var rows []string
+	for ; tp.next < len(tp.lines); tp.next++ {
+		if strings.TrimRight(tp.lines[tp.next], " ") == textFence {
+			tp.next++
+			return padRows(rows), nil
+		}
+		rows = append(rows, tp.lines[tp.next])
+	}
+	return nil, tp.errorf(line, "block of rows is never closed by %s", textFence)
+}
+
+// textRows splits the contents of a dungeon file into rows, ignoring its final newline.
+func textRows(data []byte) []string {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// padRows fills every row shorter than the longest one with spaces.
+func padRows(rows []string) []string {
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	for idx, row := range rows {
+		rows[idx] = row + strings.Repeat(" ", width-len(row))
+	}
+	return rows
+}
+
+func parseTextTeleporter(args []string) (JSONTeleporter, error) {
+	var teleporter JSONTeleporter
+	found := make(map[string]struct{})
+	for _, arg := range args {
+		key, value, err := textField(arg)
+		if err != nil {
+			return teleporter, err
+		}
+		if _, isFound := found[key]; isFound {
+			return teleporter, fmt.Errorf("duplicate teleporter field: %s", key)
+		}
+		found[key] = struct{}{}
+
+		switch key {
+		case "from", "to":
+			coord := &teleporter.From
+			if key == "to" {
+				coord = &teleporter.To
+			}
+			if *coord, err = parseCoordinate(value); err != nil {
+				return teleporter, fmt.Errorf("%s: %w", key, err)
+			}
+		case "one_way":
+			if teleporter.OneWay, err = strconv.ParseBool(value); err != nil {
+				return teleporter, fmt.Errorf("invalid teleporter one_way value: %q", value)
+			}
+		default:
+			return teleporter, fmt.Errorf("unknown teleporter field: %s", key)
+		}
+	}
+	for _, key := range [...]string{"from", "to"} {
+		if _, isFound := found[key]; !isFound {
+			return teleporter, fmt.Errorf("missing teleporter field: %s", key)
+		}
+	}
+	return teleporter, nil
+}
+
+func parseTextTerrain(args []string) (JSONTerrain, error) {
+	var terrain JSONTerrain
+	if len(args) == 0 || !strings.HasPrefix(args[0], `"`) {
+		return terrain, errors.New("terrain expects a quoted character")
+	}
+	char, err := strconv.Unquote(args[0])
+	if err != nil {
+		return terrain, fmt.Errorf("invalid terrain character %s: %w", args[0], err)
+	}
+	terrain.Char = char
+
+	found := make(map[string]struct{})
+	for _, arg := range args[1:] {
+		key, value, err := textField(arg)
+		if err != nil {
+			return terrain, err
+		}
+		if _, isFound := found[key]; isFound {
+			return terrain, fmt.Errorf("duplicate terrain field: %s", key)
+		}
+		found[key] = struct{}{}
+
+		switch key {
+		case "name":
+			terrain.Name = value
+		case "cost":
+			if terrain.Cost, err = strconv.Atoi(value); err != nil {
+				return terrain, fmt.Errorf("invalid terrain cost: %q", value)
+			}
+		case "passable":
+			passable, err := strconv.ParseBool(value)
+			if err != nil {
+				return terrain, fmt.Errorf("invalid terrain passability: %q", value)
+			}
+			terrain.Passable = &passable
+		case "direction":
+			terrain.Direction = value
+		case "image":
+			terrain.Image = value
+		case "color":
+			terrain.Color = value
+		default:
+			return terrain, fmt.Errorf("unknown terrain field: %s", key)
+		}
+	}
+	return terrain, nil
+}
+
+// parseCoordinate parses a coordinate pair written as "x,y".
+func parseCoordinate(str string) (xy.XY, error) {
+	x, y, found := strings.Cut(str, ",")
+	if !found {
+		return xy.XY{}, fmt.Errorf("invalid coordinate pair: %q", str)
+	}
+	var coord xy.XY
+	var errX, errY error
+	coord.X, errX = strconv.Atoi(x)
+	coord.Y, errY = strconv.Atoi(y)
+	if errX != nil || errY != nil {
+		return xy.XY{}, fmt.Errorf("invalid coordinate pair: %q", str)
+	}
+	return coord, nil
+}
+
+// textField splits a "key=value" token. Quoted values are unquoted.
+func textField(token string) (string, string, error) {
+	key, value, found := strings.Cut(token, "=")
+	if !found || key == "" {
+		return "", "", fmt.Errorf("expected key=value, found %q", token)
+	}
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid quoted value of %s: %w", key, err)
+		}
+		value = unquoted
+	}
+	return key, value, nil
+}
+
+// textTokens splits a directive's line into space separated tokens. Spaces within double
+// quotes do not split tokens, and the quotes are kept.
+func textTokens(line string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	inQuotes, escaped := false, false
+
+	for _, char := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes && char == '\\':
+			escaped = true
+		case char == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && (char == ' ' || char == '\t'):
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+			continue
+		}
+		token.WriteRune(char)
+	}
+
+	if inQuotes {
+		return nil, errors.New("unterminated quoted value")
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
+
+// FormatText writes jp in the plain-text format. Parsing the result yields jp back, as
+// long as its maps are rectangular. Dungeons are always written as blocks.
+func FormatText(jp JSONPlan) []byte {
+	var buf bytes.Buffer
+	coordinate := func(coord xy.XY) string {
+		return fmt.Sprintf("%d,%d", coord.X, coord.Y)
+	}
+
+	fmt.Fprintf(&buf, "start %s\n", coordinate(jp.Start))
+	fmt.Fprintf(&buf, "lost_woods %s\n", coordinate(jp.LostWoods))
+	fmt.Fprintf(&buf, "master_sword %s\n", coordinate(jp.MasterSword))
+	for _, terrain := range jp.Palette {
+		fmt.Fprintf(&buf, "terrain %s name=%s cost=%d",
+			strconv.Quote(terrain.Char), textValue(terrain.Name), terrain.Cost)
+		if terrain.Passable != nil {
+			fmt.Fprintf(&buf, " passable=%t", *terrain.Passable)
+		}
+		if terrain.Direction != "" {
+			fmt.Fprintf(&buf, " direction=%s", textValue(terrain.Direction))
+		}
+		if terrain.Image != "" {
+			fmt.Fprintf(&buf, " image=%s", textValue(terrain.Image))
+		}
+		if terrain.Color != "" {
+			fmt.Fprintf(&buf, " color=%s", textValue(terrain.Color))
+		}
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString("\nmap\n")
+	writeTextBlock(&buf, jp.MainMap)
+	for _, dungeon := range jp.Dungeons {
+		fmt.Fprintf(&buf, "\ndungeon entrance=%s start=%s goal=%s\n",
+			coordinate(dungeon.Entrance), coordinate(dungeon.Start), coordinate(dungeon.Goal))
+		writeTextBlock(&buf, dungeon.Grid)
+	}
+	if len(jp.Teleporters) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, teleporter := range jp.Teleporters {
+		fmt.Fprintf(&buf, "teleporter from=%s to=%s",
+			coordinate(teleporter.From), coordinate(teleporter.To))
+		if teleporter.OneWay {
+			buf.WriteString(" one_way=true")
+		}
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes()
+}
+
+func writeTextBlock(buf *bytes.Buffer, rows []string) {
+	buf.WriteString(textFence + "\n")
+	for _, row := range rows {
+		buf.WriteString(row + "\n")
+	}
+	buf.WriteString(textFence + "\n")
+}
+
+// textValue quotes value if it could not be parsed back otherwise.
+func textValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\"\\") {
+		return strconv.Quote(value)
+	}
+	return value
+}
@@ -0,0 +1,125 @@
+package plan
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestTextRoundTrip(t *testing.T) {
+	passable := false
+	plans := map[string]JSONPlan{
+		"default": DefaultJSONPlan(),
+		"palette": {
+			Version:     CurrentVersion,
+			MasterSword: xy.XY{X: 0, Y: 0},
+			LostWoods:   xy.XY{X: 3, Y: 0},
+			Start:       xy.XY{X: 1, Y: 1},
+			Palette: []JSONTerrain{
+				{Char: "=", Name: "road", Cost: 5, Color: "#a08060"},
+				{Char: "\"", Name: "deep swamp", Cost: 60, Passable: &passable, Image: "a b.png"},
+			},
+			MainMap: []string{"@==\"", "    "},
+		},
+		"teleporters": {
+			Version:     CurrentVersion,
+			MasterSword: xy.XY{X: 0, Y: 0},
+			LostWoods:   xy.XY{X: 3, Y: 0},
+			Start:       xy.XY{X: 1, Y: 1},
+			Palette: []JSONTerrain{
+				{Char: "v", Name: "waterfall", Cost: 30, Direction: "south", Color: "#5080e0"},
+			},
+			MainMap: []string{" v  ", " v  "},
+			Teleporters: []JSONTeleporter{
+				{From: xy.XY{X: 2, Y: 0}, To: xy.XY{X: 3, Y: 1}},
+				{From: xy.XY{X: 0, Y: 1}, To: xy.XY{X: 2, Y: 1}, OneWay: true},
+			},
+		},
+	}
+
+	for name, jplan := range plans {
+		parsed, err := ParseText(FormatText(jplan), "")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !reflect.DeepEqual(parsed, jplan) {
+			t.Errorf("%s: parsed plan differs from the formatted one", name)
+		}
+	}
+}
+
+func TestParseText(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "dungeon.txt"), []byte("#  \n  #\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	text := "// a tiny plan\n" +
+		"start 0,1\nlost_woods 3,0\nmaster_sword 0,0\n" +
+		"\nmap\n```\n@@_*\n\n```\n" +
+		"dungeon entrance=2,1 start=1,0 goal=0,1 file=dungeon.txt\n"
+	jplan, err := ParseText([]byte(text), dir)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	want := JSONPlan{
+		Version:     CurrentVersion,
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 3, Y: 0},
+		Start:       xy.XY{X: 0, Y: 1},
+		// trailing spaces are restored
+		MainMap: []string{"@@_*", "    "},
+		Dungeons: []JSONDungeon{{
+			Grid:     []string{"#  ", "  #"},
+			Entrance: xy.XY{X: 2, Y: 1},
+			Start:    xy.XY{X: 1, Y: 0},
+			Goal:     xy.XY{X: 0, Y: 1},
+		}},
+		Dir: dir,
+	}
+	if !reflect.DeepEqual(jplan, want) {
+		t.Fatalf("Unexpected plan: %+v", jplan)
+	}
+	if err := jplan.Validate(); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+}
+
+func TestParseTextErrors(t *testing.T) {
+	header := "start 0,0\nlost_woods 1,0\nmaster_sword 2,0\n"
+	tests := []struct {
+		name string
+		text string
+		line int
+	}{
+		{"unknown directive", header + "castle 1,1\n", 4},
+		{"bad coordinate", "start 0;0\n", 1},
+		{"duplicate start", header + "start 1,1\n", 4},
+		{"unclosed block", header + "\nmap\n```\n@@@\n", 5},
+		{"missing block", header + "map\nstart 1,1\n", 4},
+		{"missing map", header, 4},
+		{"missing dungeon field", header + "map\n```\n   \n```\ndungeon start=0,0 goal=1,0\n", 8},
+		{"bad terrain cost", header + "terrain \"=\" name=road cost=cheap\n", 4},
+		{"unterminated quote", header + "terrain \"= name=road\n", 4},
+		{"duplicate terrain field", header + "terrain \"=\" name=road cost=5 cost=1\n", 4},
+		{"missing teleporter field", header + "teleporter from=1,1\n", 4},
+		{"bad teleporter field", header + "teleporter from=1,1 to=2,2 one_way=maybe\n", 4},
+	}
+
+	for _, test := range tests {
+		_, err := ParseText([]byte(test.text), "")
+		var textErr *TextError
+		if !errors.As(err, &textErr) {
+			t.Errorf("%s: expected a TextError, got %v", test.name, err)
+			continue
+		}
+		if textErr.Line != test.line {
+			t.Errorf("%s: expected error at line %d, got %v", test.name, test.line, err)
+		}
+	}
+}
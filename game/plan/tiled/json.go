@@ -0,0 +1,194 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// This file decodes maps and tilesets in Tiled's JSON format.
+
+type jsonMap struct {
+	Width      int           `json:"width"`
+	Height     int           `json:"height"`
+	TileWidth  int           `json:"tilewidth"`
+	TileHeight int           `json:"tileheight"`
+	Infinite   bool          `json:"infinite"`
+	Tilesets   []jsonTileset `json:"tilesets"`
+	Layers     []jsonLayer   `json:"layers"`
+}
+
+type jsonTileset struct {
+	FirstGID uint32     `json:"firstgid"`
+	Source   string     `json:"source"`
+	Name     string     `json:"name"`
+	Tiles    []jsonTile `json:"tiles"`
+}
+
+type jsonTile struct {
+	ID uint32 `json:"id"`
+	// Type was renamed to Class in Tiled 1.9.
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	Image      string         `json:"image"`
+	Properties []jsonProperty `json:"properties"`
+}
+
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Data        json.RawMessage `json:"data"`
+	Objects     []jsonObject    `json:"objects"`
+	Layers      []jsonLayer     `json:"layers"`
+}
+
+type jsonObject struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	X          float64        `json:"x"`
+	Y          float64        `json:"y"`
+	Width      float64        `json:"width"`
+	Height     float64        `json:"height"`
+	GID        uint32         `json:"gid"`
+	Properties []jsonProperty `json:"properties"`
+}
+
+type jsonProperty struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+func decodeJSON(data []byte, dir string) (*tiledMap, error) {
+	var jm jsonMap
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, err
+	}
+	if jm.Infinite {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+
+	m := tiledMap{
+		dir: dir, width: jm.Width, height: jm.Height,
+		tileWidth: jm.TileWidth, tileHeight: jm.TileHeight,
+	}
+	for _, jts := range jm.Tilesets {
+		ts, err := jts.decode(dir)
+		if err != nil {
+			return nil, err
+		}
+		m.tilesets = append(m.tilesets, ts)
+	}
+
+	layers, err := decodeJSONLayers(jm.Layers)
+	if err != nil {
+		return nil, err
+	}
+	m.layers = layers
+	return &m, nil
+}
+
+// decode decodes the tileset, reading it from its source file if it is external.
+func (jts jsonTileset) decode(dir string) (tileset, error) {
+	firstGID := jts.FirstGID
+	if jts.Source != "" {
+		path := resolve(dir, jts.Source)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return tileset{}, fmt.Errorf("failed to read tileset: %w", err)
+		}
+		if filepath.Ext(path) == ".tsx" {
+			return decodeTSX(data, filepath.Dir(path), firstGID)
+		}
+
+		jts = jsonTileset{}
+		if err := json.Unmarshal(data, &jts); err != nil {
+			return tileset{}, fmt.Errorf("failed to decode tileset %s: %w", path, err)
+		}
+		dir = filepath.Dir(path)
+	}
+
+	ts := tileset{name: jts.Name, firstGID: firstGID, tiles: make(map[uint32]tile)}
+	for _, jt := range jts.Tiles {
+		t := tile{
+			id: jt.ID, class: jt.Class, image: resolve(dir, jt.Image),
+			properties: jsonProperties(jt.Properties),
+		}
+		if t.class == "" {
+			t.class = jt.Type
+		}
+		ts.tiles[jt.ID] = t
+	}
+	return ts, nil
+}
+
+// decodeJSONLayers decodes layers, flattening group layers into their children.
+func decodeJSONLayers(jls []jsonLayer) ([]layer, error) {
+	var layers []layer
+	for _, jl := range jls {
+		switch jl.Type {
+		case "tilelayer":
+			l := layer{name: jl.Name, isTiles: true}
+			var err error
+			if jl.Encoding == "base64" {
+				var text string
+				if err := json.Unmarshal(jl.Data, &text); err != nil {
+					return nil, fmt.Errorf("layer %q has invalid data: %w", jl.Name, err)
+				}
+				l.data, err = decodeBase64(text, jl.Compression)
+			} else {
+				err = json.Unmarshal(jl.Data, &l.data)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("layer %q has invalid data: %w", jl.Name, err)
+			}
+			layers = append(layers, l)
+
+		case "objectgroup":
+			l := layer{name: jl.Name}
+			for _, jo := range jl.Objects {
+				obj := object{
+					id: jo.ID, name: jo.Name, class: jo.Class,
+					x: jo.X, y: jo.Y, width: jo.Width, height: jo.Height, gid: jo.GID,
+					properties: jsonProperties(jo.Properties),
+				}
+				if obj.class == "" {
+					obj.class = jo.Type
+				}
+				l.objects = append(l.objects, obj)
+			}
+			layers = append(layers, l)
+
+		case "group":
+			children, err := decodeJSONLayers(jl.Layers)
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, children...)
+		}
+	}
+	return layers, nil
+}
+
+// jsonProperties maps each property's name to its value, formatted as a string.
+func jsonProperties(jps []jsonProperty) map[string]string {
+	properties := make(map[string]string, len(jps))
+	for _, jp := range jps {
+		switch value := jp.Value.(type) {
+		case string:
+			properties[jp.Name] = value
+		case bool:
+			properties[jp.Name] = strconv.FormatBool(value)
+		case float64:
+			properties[jp.Name] = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			properties[jp.Name] = fmt.Sprint(value)
+		}
+	}
+	return properties
+}
@@ -0,0 +1,26 @@
+<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="terrain" tilewidth="32" tileheight="32" tilecount="4" columns="0">
+ <tile id="0">
+  <properties>
+   <property name="terrain" value="grass"/>
+  </properties>
+ </tile>
+ <tile id="1">
+  <properties>
+   <property name="terrain" value="forest"/>
+  </properties>
+ </tile>
+ <tile id="2" class="road">
+  <properties>
+   <property name="color" type="color" value="#ffa08060"/>
+   <property name="cost" type="int" value="5"/>
+  </properties>
+ </tile>
+ <tile id="3" type="cliff">
+  <properties>
+   <property name="color" type="color" value="#ff5a4a3a"/>
+   <property name="cost" type="int" value="0"/>
+   <property name="passable" type="bool" value="false"/>
+  </properties>
+ </tile>
+</tileset>
@@ -0,0 +1,451 @@
+// Package tiled imports maps made with the Tiled map editor (https://www.mapeditor.org)
+// as plans. Both the JSON (.tmj, .json) and the XML (.tmx) formats are supported, along
+// with embedded and external tilesets.
+//
+// The main map is read from the first tile layer of a map. Each tile is mapped to a
+// terrain through its custom properties:
+//
+//   - "terrain" names a predefined terrain, such as "forest", which the tile stands for.
+//     The remaining properties are then ignored.
+//   - "cost" (int) is the terrain's traversal cost. It is required for other tiles.
+//   - "passable" (bool) defines whether the terrain may be traversed. It defaults to
+//     true.
+//   - "name" (string) is the terrain's name. It defaults to the tile's class.
+//   - "color" (color) is the color the terrain is drawn with, unless the tile has an
+//     image of its own, as in image collection tilesets.
+//
+// The points of interest are objects of any object layer, whose class (or, if it has no
+// class, whose name) is "start", "gate", "sword" or "dungeon". A dungeon object has a
+// "map" property, which is the path to another Tiled map holding the dungeon's grid. In
+// that map, tiles whose "passable" property is false are walls, and the "start" and
+// "goal" objects place the dungeon's start and goal.
+package tiled
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// Load reads the Tiled map at path and converts it to a plan, which is validated.
+func Load(path string) (plan.Plan, error) {
+	jplan, err := LoadJSONPlan(path)
+	if err != nil {
+		return plan.Plan{}, err
+	}
+	if err := jplan.Validate(); err != nil {
+		return plan.Plan{}, fmt.Errorf("invalid plan: %w", err)
+	}
+	return jplan.ToPlan(), nil
+}
+
+// LoadJSONPlan reads the Tiled map at path and converts it to a JSON plan, which is not
+// validated. Custom terrains are assigned characters of their own.
+func LoadJSONPlan(path string) (plan.JSONPlan, error) {
+	m, err := readMap(path)
+	if err != nil {
+		return plan.JSONPlan{}, err
+	}
+	layer, err := m.terrainLayer()
+	if err != nil {
+		return plan.JSONPlan{}, err
+	}
+
+	jplan := plan.JSONPlan{Version: plan.CurrentVersion}
+	palette := newPalette()
+	for y := 0; y < m.height; y++ {
+		var row strings.Builder
+		for x := 0; x < m.width; x++ {
+			gid := layer.data[y*m.width+x]
+			char, err := palette.char(m, gid)
+			if err != nil {
+				return plan.JSONPlan{}, fmt.Errorf("tile at (%d, %d): %w", x, y, err)
+			}
+			row.WriteByte(char)
+		}
+		jplan.MainMap = append(jplan.MainMap, row.String())
+	}
+	jplan.Palette = palette.terrains
+
+	found := make(map[string]int)
+	for _, obj := range m.objects() {
+		at := m.tileOf(obj)
+		var role string
+		var coord *xy.XY
+		switch obj.kind() {
+		case "start":
+			role, coord = "start", &jplan.Start
+		case "gate", "lost_woods":
+			role, coord = "gate", &jplan.LostWoods
+		case "sword", "master_sword":
+			role, coord = "sword", &jplan.MasterSword
+		case "dungeon":
+			dungeon, err := loadDungeon(m, obj)
+			if err != nil {
+				return plan.JSONPlan{}, fmt.Errorf("dungeon object %d: %w", obj.id, err)
+			}
+			dungeon.Entrance = at
+			jplan.Dungeons = append(jplan.Dungeons, dungeon)
+			continue
+		default:
+			continue
+		}
+		if err := claim(found, role, obj); err != nil {
+			return plan.JSONPlan{}, err
+		}
+		*coord = at
+	}
+	for _, kind := range [...]string{"start", "gate", "sword"} {
+		if _, isFound := found[kind]; !isFound {
+			return plan.JSONPlan{}, fmt.Errorf("map has no %q object", kind)
+		}
+	}
+
+	return jplan, nil
+}
+
+// loadDungeon reads the dungeon referred to by the "map" property of obj, which is an
+// object of m.
+func loadDungeon(m *tiledMap, obj object) (plan.JSONDungeon, error) {
+	var dungeon plan.JSONDungeon
+	path, ok := obj.properties["map"]
+	if !ok {
+		return dungeon, fmt.Errorf("missing \"map\" property")
+	}
+	dm, err := readMap(m.resolve(path))
+	if err != nil {
+		return dungeon, err
+	}
+	layer, err := dm.terrainLayer()
+	if err != nil {
+		return dungeon, err
+	}
+
+	for y := 0; y < dm.height; y++ {
+		var row strings.Builder
+		for x := 0; x < dm.width; x++ {
+			tile, err := dm.tile(layer.data[y*dm.width+x])
+			if err != nil {
+				return dungeon, fmt.Errorf("tile at (%d, %d): %w", x, y, err)
+			}
+			passable, err := tile.passable()
+			if err != nil {
+				return dungeon, err
+			}
+
+			if passable {
+				row.WriteByte(' ')
+			} else {
+				row.WriteByte('#')
+			}
+		}
+		dungeon.Grid = append(dungeon.Grid, row.String())
+	}
+
+	found := make(map[string]int)
+	for _, obj := range dm.objects() {
+		var coord *xy.XY
+		switch obj.kind() {
+		case "start":
+			coord = &dungeon.Start
+		case "goal":
+			coord = &dungeon.Goal
+		default:
+			continue
+		}
+		if err := claim(found, obj.kind(), obj); err != nil {
+			return dungeon, err
+		}
+		*coord = dm.tileOf(obj)
+	}
+	for _, kind := range [...]string{"start", "goal"} {
+		if _, isFound := found[kind]; !isFound {
+			return dungeon, fmt.Errorf("dungeon map has no %q object", kind)
+		}
+	}
+	return dungeon, nil
+}
+
+// claim records that obj plays role. found maps each role claimed so far to the ID of
+// the object which plays it, as no two objects may play the same role.
+func claim(found map[string]int, role string, obj object) error {
+	if first, isFound := found[role]; isFound {
+		return fmt.Errorf("object %d duplicates the %q object %d", obj.id, role, first)
+	}
+	found[role] = obj.id
+	return nil
+}
+
+// palette assigns a character to each distinct tile of the main map.
+type palette struct {
+	chars    map[uint32]byte
+	terrains []plan.JSONTerrain
+	// free are the characters which have not been assigned to custom terrains yet.
+	free []byte
+}
+
+func newPalette() *palette {
+	return &palette{
+		chars: make(map[uint32]byte),
+		free:  []byte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"),
+	}
+}
+
+// char returns the character of the terrain which the tile of gid stands for. Flipped
+// and rotated tiles share the character of the tile they are drawn from.
+func (p *palette) char(m *tiledMap, gid uint32) (byte, error) {
+	gid &^= gidFlags
+	if char, ok := p.chars[gid]; ok {
+		return char, nil
+	}
+	tile, err := m.tile(gid)
+	if err != nil {
+		return 0, err
+	}
+
+	if name, ok := tile.properties["terrain"]; ok {
+		for _, terrain := range plan.DefaultPalette() {
+			if terrain.Name() == name {
+				p.chars[gid] = byte(terrain.Char())
+				return p.chars[gid], nil
+			}
+		}
+		return 0, fmt.Errorf("unknown predefined terrain: %q", name)
+	}
+
+	terrain, err := tile.terrain()
+	if err != nil {
+		return 0, err
+	}
+	if len(p.free) == 0 {
+		return 0, fmt.Errorf("map has too many distinct tiles")
+	}
+	char := p.free[0]
+	p.free = p.free[1:]
+
+	terrain.Char = string(char)
+	p.chars[gid] = char
+	p.terrains = append(p.terrains, terrain)
+	return char, nil
+}
+
+// tiledMap is a Tiled map, regardless of the format it was read from.
+type tiledMap struct {
+	// dir is the directory of the map's file, which its paths are relative to.
+	dir string
+
+	width, height         int
+	tileWidth, tileHeight int
+
+	tilesets []tileset
+	layers   []layer
+}
+
+// tileset is a tileset of a map. Its tiles are indexed by their local IDs.
+type tileset struct {
+	name     string
+	firstGID uint32
+	tiles    map[uint32]tile
+}
+
+type tile struct {
+	id    uint32
+	class string
+	// image is the path to the tile's own image, if any. It is resolved against the
+	// directory of the tile's tileset.
+	image      string
+	properties map[string]string
+}
+
+// layer is either a tile layer, whose data holds a GID per tile, or an object layer.
+type layer struct {
+	name    string
+	isTiles bool
+	data    []uint32
+	objects []object
+}
+
+type object struct {
+	id          int
+	name, class string
+	// x and y are measured in pixels. Tile objects, which have a gid, are placed by their
+	// bottom left corner, while other objects are placed by their top left corner.
+	x, y, width, height float64
+	gid                 uint32
+	properties          map[string]string
+}
+
+// Flags stored in the highest bits of a GID, which define how the tile is flipped.
+const gidFlags = 0xf0000000
+
+// readMap reads the map at path, in the format defined by its extension.
+func readMap(path string) (*tiledMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read map: %w", err)
+	}
+
+	var m *tiledMap
+	if strings.EqualFold(filepath.Ext(path), ".tmx") {
+		m, err = decodeTMX(data, filepath.Dir(path))
+	} else {
+		m, err = decodeJSON(data, filepath.Dir(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode map %s: %w", path, err)
+	}
+
+	for _, layer := range m.layers {
+		if layer.isTiles && len(layer.data) != m.width*m.height {
+			return nil, fmt.Errorf(
+				"layer %q of map %s has %d tiles, expected %d",
+				layer.name, path, len(layer.data), m.width*m.height,
+			)
+		}
+	}
+	return m, nil
+}
+
+// resolve returns path relative to the map's directory, unless it is absolute.
+func (m *tiledMap) resolve(path string) string {
+	return resolve(m.dir, path)
+}
+
+func resolve(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// terrainLayer returns the first tile layer of the map.
+func (m *tiledMap) terrainLayer() (layer, error) {
+	for _, layer := range m.layers {
+		if layer.isTiles {
+			return layer, nil
+		}
+	}
+	return layer{}, fmt.Errorf("map has no tile layer")
+}
+
+// objects returns the objects of every object layer of the map.
+func (m *tiledMap) objects() []object {
+	var objects []object
+	for _, layer := range m.layers {
+		objects = append(objects, layer.objects...)
+	}
+	return objects
+}
+
+// tile returns the tile of gid.
+func (m *tiledMap) tile(gid uint32) (tile, error) {
+	gid &^= gidFlags
+	if gid == 0 {
+		return tile{}, fmt.Errorf("tile is empty")
+	}
+
+	// the tile belongs to the tileset with the highest first GID not greater than gid
+	var owner *tileset
+	for idx := range m.tilesets {
+		ts := &m.tilesets[idx]
+		if ts.firstGID <= gid && (owner == nil || ts.firstGID > owner.firstGID) {
+			owner = ts
+		}
+	}
+	if owner == nil {
+		return tile{}, fmt.Errorf("tile %d belongs to no tileset", gid)
+	}
+
+	tile, ok := owner.tiles[gid-owner.firstGID]
+	if !ok {
+		// a tile without any properties is not listed by its tileset
+		tile.id = gid - owner.firstGID
+	}
+	return tile, nil
+}
+
+// tileOf returns the coordinates of the tile on which the center of obj lies.
+func (m *tiledMap) tileOf(obj object) xy.XY {
+	centerX, centerY := obj.x+obj.width/2, obj.y+obj.height/2
+	if obj.gid != 0 {
+		centerY = obj.y - obj.height/2
+	}
+	return xy.XY{
+		X: int(math.Floor(centerX / float64(m.tileWidth))),
+		Y: int(math.Floor(centerY / float64(m.tileHeight))),
+	}
+}
+
+// kind returns the kind of point of interest the object stands for.
+func (o object) kind() string {
+	if o.class != "" {
+		return strings.ToLower(o.class)
+	}
+	return strings.ToLower(o.name)
+}
+
+func (t tile) passable() (bool, error) {
+	value, ok := t.properties["passable"]
+	if !ok {
+		return true, nil
+	}
+	passable, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("tile %d has invalid \"passable\" property: %q", t.id, value)
+	}
+	return passable, nil
+}
+
+// terrain returns the custom terrain which the tile stands for, without its character.
+func (t tile) terrain() (plan.JSONTerrain, error) {
+	var terrain plan.JSONTerrain
+
+	value, ok := t.properties["cost"]
+	if !ok {
+		return terrain, fmt.Errorf("tile %d has neither a \"terrain\" nor a \"cost\" property", t.id)
+	}
+	cost, err := strconv.ParseFloat(value, 64)
+	if err != nil || cost != math.Trunc(cost) {
+		return terrain, fmt.Errorf("tile %d has invalid \"cost\" property: %q", t.id, value)
+	}
+	terrain.Cost = int(cost)
+
+	passable, err := t.passable()
+	if err != nil {
+		return terrain, err
+	}
+	if !passable {
+		terrain.Passable = &passable
+	}
+
+	terrain.Name = t.properties["name"]
+	if terrain.Name == "" {
+		terrain.Name = t.class
+	}
+	if terrain.Name == "" {
+		terrain.Name = fmt.Sprintf("tile %d", t.id)
+	}
+
+	if t.image != "" {
+		terrain.Image = t.image
+	} else if color, ok := t.properties["color"]; ok {
+		terrain.Color = tiledColor(color)
+	} else {
+		return terrain, fmt.Errorf("tile %d has neither an image nor a \"color\" property", t.id)
+	}
+	return terrain, nil
+}
+
+// tiledColor converts a color in Tiled's "#aarrggbb" format to the "#rrggbb" format.
+func tiledColor(color string) string {
+	if len(color) == len("#aarrggbb") {
+		return "#" + color[3:]
+	}
+	return color
+}
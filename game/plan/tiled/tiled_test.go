@@ -0,0 +1,118 @@
+package tiled
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestLoadJSONPlan(t *testing.T) {
+	impassable := false
+	want := plan.JSONPlan{
+		Version:     plan.CurrentVersion,
+		MasterSword: xy.XY{X: 1, Y: 2},
+		LostWoods:   xy.XY{X: 4, Y: 1},
+		Start:       xy.XY{X: 1, Y: 1},
+		Palette: []plan.JSONTerrain{
+			{Char: "a", Name: "road", Cost: 5, Color: "#a08060"},
+			{Char: "b", Name: "cliff", Cost: 0, Passable: &impassable, Color: "#5a4a3a"},
+		},
+		MainMap: []string{"@@@@@@", "@ aa @", "@ b  @", "@@@@@@"},
+		Dungeons: []plan.JSONDungeon{{
+			Grid:     []string{"####", "#  #", "## #"},
+			Entrance: xy.XY{X: 4, Y: 2},
+			Start:    xy.XY{X: 1, Y: 1},
+			Goal:     xy.XY{X: 2, Y: 2},
+		}},
+	}
+
+	for _, path := range [...]string{"testdata/overworld.tmj", "testdata/overworld.tmx"} {
+		jplan, err := LoadJSONPlan(path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+		if !reflect.DeepEqual(jplan, want) {
+			t.Errorf("%s: unexpected plan: %+v", path, jplan)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	gamePlan, err := Load("testdata/overworld.tmx")
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	road := gamePlan.Grid[1][2]
+	if road.Name() != "road" || road.Cost() != 5 {
+		t.Errorf("Unexpected road terrain: %s costing %d", road.Name(), road.Cost())
+	}
+	if gamePlan.Grid[2][2].Traversable() {
+		t.Error("Expected the cliff not to be traversable")
+	}
+	if gamePlan.Grid[0][0] != plan.Forest {
+		t.Error("Expected the predefined forest terrain")
+	}
+}
+
+func TestPaletteFlippedTile(t *testing.T) {
+	m, err := readMap("testdata/overworld.tmj")
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	// the road tile, as drawn and as flipped horizontally and diagonally
+	const road, flipped = 3, 3 | 0x80000000 | 0x20000000
+	p := newPalette()
+	char, err := p.char(m, road)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	flippedChar, err := p.char(m, flipped)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if flippedChar != char {
+		t.Errorf("Expected the flipped tile to share %q, got %q", char, flippedChar)
+	}
+	if len(p.terrains) != 1 {
+		t.Errorf("Expected a single terrain, got %d", len(p.terrains))
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load("testdata/missing.tmj"); err == nil {
+		t.Error("Expected a missing map to be reported")
+	}
+	if _, err := Load("testdata/terrain.tsj"); err == nil {
+		t.Error("Expected a tileset to be rejected as a map")
+	}
+}
+
+func TestLoadDuplicateObject(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range [...]string{"terrain.tsj", "dungeon.tmj", "overworld.tmj"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name == "overworld.tmj" {
+			// a second start object, right before the first one
+			second := `{"id": 5, "class": "start", "x": 80, "y": 48, "point": true},`
+			data = []byte(strings.Replace(string(data), `"objects": [`, `"objects": [`+second, 1))
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := LoadJSONPlan(filepath.Join(dir, "overworld.tmj"))
+	if err == nil || !strings.Contains(err.Error(), `"start"`) {
+		t.Errorf("Expected the duplicated start object to be reported, got %v", err)
+	}
+}
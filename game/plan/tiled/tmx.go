@@ -0,0 +1,265 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// This file decodes maps and tilesets in Tiled's XML format, along with the tile layer
+// encodings shared by both formats.
+
+type xmlMap struct {
+	Width      int          `xml:"width,attr"`
+	Height     int          `xml:"height,attr"`
+	TileWidth  int          `xml:"tilewidth,attr"`
+	TileHeight int          `xml:"tileheight,attr"`
+	Infinite   bool         `xml:"infinite,attr"`
+	Tilesets   []xmlTileset `xml:"tileset"`
+	// Layers holds the map's layers in order, whatever their kind.
+	Layers []xmlLayer `xml:",any"`
+}
+
+type xmlTileset struct {
+	FirstGID uint32    `xml:"firstgid,attr"`
+	Source   string    `xml:"source,attr"`
+	Name     string    `xml:"name,attr"`
+	Tiles    []xmlTile `xml:"tile"`
+}
+
+type xmlTile struct {
+	ID uint32 `xml:"id,attr"`
+	// Type was renamed to Class in Tiled 1.9.
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	Image      xmlImage      `xml:"image"`
+	Properties []xmlProperty `xml:"properties>property"`
+}
+
+type xmlImage struct {
+	Source string `xml:"source,attr"`
+}
+
+type xmlLayer struct {
+	XMLName xml.Name
+	Name    string      `xml:"name,attr"`
+	Data    xmlData     `xml:"data"`
+	Objects []xmlObject `xml:"object"`
+	Layers  []xmlLayer  `xml:",any"`
+}
+
+type xmlData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Text        string `xml:",chardata"`
+	Tiles       []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+}
+
+type xmlObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Properties []xmlProperty `xml:"properties>property"`
+}
+
+type xmlProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	// Text holds the value of multiline string properties.
+	Text string `xml:",chardata"`
+}
+
+func decodeTMX(data []byte, dir string) (*tiledMap, error) {
+	var xm xmlMap
+	if err := xml.Unmarshal(data, &xm); err != nil {
+		return nil, err
+	}
+	if xm.Infinite {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+
+	m := tiledMap{
+		dir: dir, width: xm.Width, height: xm.Height,
+		tileWidth: xm.TileWidth, tileHeight: xm.TileHeight,
+	}
+	for _, xts := range xm.Tilesets {
+		ts, err := xts.decode(dir)
+		if err != nil {
+			return nil, err
+		}
+		m.tilesets = append(m.tilesets, ts)
+	}
+
+	layers, err := decodeXMLLayers(xm.Layers)
+	if err != nil {
+		return nil, err
+	}
+	m.layers = layers
+	return &m, nil
+}
+
+// decodeTSX decodes an external tileset in the XML format, found in dir.
+func decodeTSX(data []byte, dir string, firstGID uint32) (tileset, error) {
+	var xts xmlTileset
+	if err := xml.Unmarshal(data, &xts); err != nil {
+		return tileset{}, fmt.Errorf("failed to decode tileset: %w", err)
+	}
+	xts.FirstGID, xts.Source = firstGID, ""
+	return xts.decode(dir)
+}
+
+// decode decodes the tileset, reading it from its source file if it is external.
+func (xts xmlTileset) decode(dir string) (tileset, error) {
+	if xts.Source != "" {
+		path := resolve(dir, xts.Source)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return tileset{}, fmt.Errorf("failed to read tileset: %w", err)
+		}
+		if filepath.Ext(path) != ".tsx" {
+			return jsonTileset{FirstGID: xts.FirstGID, Source: xts.Source}.decode(dir)
+		}
+		return decodeTSX(data, filepath.Dir(path), xts.FirstGID)
+	}
+
+	ts := tileset{name: xts.Name, firstGID: xts.FirstGID, tiles: make(map[uint32]tile)}
+	for _, xt := range xts.Tiles {
+		t := tile{
+			id: xt.ID, class: xt.Class, image: resolve(dir, xt.Image.Source),
+			properties: xmlProperties(xt.Properties),
+		}
+		if t.class == "" {
+			t.class = xt.Type
+		}
+		ts.tiles[xt.ID] = t
+	}
+	return ts, nil
+}
+
+// decodeXMLLayers decodes layers, flattening group layers into their children.
+func decodeXMLLayers(xls []xmlLayer) ([]layer, error) {
+	var layers []layer
+	for _, xl := range xls {
+		switch xl.XMLName.Local {
+		case "layer":
+			l := layer{name: xl.Name, isTiles: true}
+			var err error
+			switch xl.Data.Encoding {
+			case "csv":
+				l.data, err = decodeCSV(xl.Data.Text)
+			case "base64":
+				l.data, err = decodeBase64(xl.Data.Text, xl.Data.Compression)
+			case "":
+				for _, tile := range xl.Data.Tiles {
+					l.data = append(l.data, tile.GID)
+				}
+			default:
+				err = fmt.Errorf("unsupported encoding: %q", xl.Data.Encoding)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("layer %q has invalid data: %w", xl.Name, err)
+			}
+			layers = append(layers, l)
+
+		case "objectgroup":
+			l := layer{name: xl.Name}
+			for _, xo := range xl.Objects {
+				obj := object{
+					id: xo.ID, name: xo.Name, class: xo.Class,
+					x: xo.X, y: xo.Y, width: xo.Width, height: xo.Height, gid: xo.GID,
+					properties: xmlProperties(xo.Properties),
+				}
+				if obj.class == "" {
+					obj.class = xo.Type
+				}
+				l.objects = append(l.objects, obj)
+			}
+			layers = append(layers, l)
+
+		case "group":
+			children, err := decodeXMLLayers(xl.Layers)
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, children...)
+		}
+	}
+	return layers, nil
+}
+
+func xmlProperties(xps []xmlProperty) map[string]string {
+	properties := make(map[string]string, len(xps))
+	for _, xp := range xps {
+		if xp.Value == "" {
+			properties[xp.Name] = xp.Text
+		} else {
+			properties[xp.Name] = xp.Value
+		}
+	}
+	return properties
+}
+
+func decodeCSV(text string) ([]uint32, error) {
+	var gids []uint32
+	for _, field := range strings.Split(strings.TrimSpace(text), ",") {
+		gid, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		gids = append(gids, uint32(gid))
+	}
+	return gids, nil
+}
+
+// decodeBase64 decodes base64 encoded tile data, which holds a little-endian GID per tile
+// and may be compressed with zlib or gzip.
+func decodeBase64(text, compression string) ([]uint32, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader = bytes.NewReader(data)
+	switch compression {
+	case "":
+	case "zlib":
+		if reader, err = zlib.NewReader(reader); err != nil {
+			return nil, err
+		}
+	case "gzip":
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression: %q", compression)
+	}
+	if data, err = io.ReadAll(reader); err != nil {
+		return nil, err
+	}
+
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("data length is not a multiple of 4: %d", len(data))
+	}
+	gids := make([]uint32, len(data)/4)
+	for idx := range gids {
+		gids[idx] = binary.LittleEndian.Uint32(data[idx*4:])
+	}
+	return gids, nil
+}
@@ -0,0 +1,158 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// Kinds of problems reported by the validation of a JSON plan. Every problem is a
+// *ValidationError which wraps one of these, so they may be checked through errors.Is.
+var (
+	ErrEmptyGrid      = errors.New("grid has no rows")
+	ErrRowLength      = errors.New("row has invalid length")
+	ErrUnknownChar    = errors.New("unknown character")
+	ErrInvalidTerrain = errors.New("invalid terrain")
+	ErrDuplicateChar  = errors.New("duplicate character")
+	ErrOutOfGrid      = errors.New("coordinate pair is out of the grid")
+	ErrImpassable     = errors.New("coordinate pair is on non traversable block")
+	ErrOverlap        = errors.New("points of interest overlap")
+	ErrUnreachable    = errors.New("goal is unreachable from start")
+)
+
+// ValidationError is a single problem found in a JSON plan.
+type ValidationError struct {
+	// Path is the JSON path to the offending value, such as "dungeons[1].grid[3]".
+	Path string
+	// Row and Col locate the offending block within the grid which Path refers to. They
+	// are -1 if the problem does not regard a single row or column.
+	Row, Col int
+	Err      error
+}
+
+func (ve *ValidationError) Error() string {
+	switch {
+	case ve.Row >= 0 && ve.Col >= 0:
+		return fmt.Sprintf("%s (row %d, column %d): %v", ve.Path, ve.Row, ve.Col, ve.Err)
+	case ve.Row >= 0:
+		return fmt.Sprintf("%s (row %d): %v", ve.Path, ve.Row, ve.Err)
+	default:
+		return fmt.Sprintf("%s: %v", ve.Path, ve.Err)
+	}
+}
+
+func (ve *ValidationError) Unwrap() error {
+	return ve.Err
+}
+
+// ValidationErrors are all problems found in a JSON plan. Both errors.Is and errors.As
+// look through every one of them.
+type ValidationErrors []*ValidationError
+
+func (ves ValidationErrors) Error() string {
+	messages := make([]string, len(ves))
+	for idx, ve := range ves {
+		messages[idx] = ve.Error()
+	}
+	return fmt.Sprintf("%d problem(s) found: %s", len(ves), strings.Join(messages, "; "))
+}
+
+func (ves ValidationErrors) Is(target error) bool {
+	for _, ve := range ves {
+		if errors.Is(ve, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ves ValidationErrors) As(target any) bool {
+	for _, ve := range ves {
+		if errors.As(ve, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// validator collects the problems found while validating a JSON plan.
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(path string, row, col int, err error) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Row: row, Col: col, Err: err})
+}
+
+// err returns the collected problems, or nil if there are none.
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// rows checks whether rows form a non-empty rectangular grid whose characters are all
+// known. It reports whether the grid's blocks may be indexed.
+func (v *validator) rows(path string, rows []string, known func(byte) bool) bool {
+	if len(rows) == 0 {
+		v.add(path, -1, -1, ErrEmptyGrid)
+		return false
+	}
+
+	isGrid := true
+	width := len(rows[0])
+	for y, row := range rows {
+		if len(row) != width || width == 0 {
+			err := fmt.Errorf("%w: %d", ErrRowLength, len(row))
+			v.add(fmt.Sprintf("%s[%d]", path, y), y, -1, err)
+			isGrid = false
+			continue
+		}
+
+		for x := 0; x < len(row); x++ {
+			if !known(row[x]) {
+				err := fmt.Errorf("%w: %q", ErrUnknownChar, row[x])
+				v.add(fmt.Sprintf("%s[%d]", path, y), y, x, err)
+			}
+		}
+	}
+	return isGrid
+}
+
+// coordinate checks whether coord, which is found at path, lies on a passable block of
+// rows. If rows is nil, as its grid is invalid, the coordinate is not checked.
+func (v *validator) coordinate(path string, coord xy.XY, rows []string, passable func(byte) bool) {
+	if rows == nil {
+		return
+	}
+	if !rowsBounds(rows).Contains(coord) {
+		v.add(path, coord.Y, coord.X, fmt.Errorf("%w: (%d, %d)", ErrOutOfGrid, coord.X, coord.Y))
+		return
+	}
+	if !passable(rows[coord.Y][coord.X]) {
+		v.add(path, coord.Y, coord.X, fmt.Errorf("%w: (%d, %d)", ErrImpassable, coord.X, coord.Y))
+	}
+}
+
+// pointOfInterest is a named coordinate of a grid.
+type pointOfInterest struct {
+	path  string
+	coord xy.XY
+}
+
+// overlaps reports every point of interest which lies on the same block as a previous
+// one.
+func (v *validator) overlaps(points ...pointOfInterest) {
+	seen := make(map[xy.XY]string, len(points))
+	for _, point := range points {
+		if previous, isSeen := seen[point.coord]; isSeen {
+			err := fmt.Errorf("%w: %s is at the same block", ErrOverlap, previous)
+			v.add(point.path, point.coord.Y, point.coord.X, err)
+			continue
+		}
+		seen[point.coord] = point.path
+	}
+}
@@ -0,0 +1,129 @@
+package plan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestValidationErrors(t *testing.T) {
+	jplan := JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 0, Y: 0},
+		Start:       xy.XY{X: 9, Y: 1},
+		MainMap:     []string{"@ ?_*", "  %  "},
+		Dungeons: []JSONDungeon{{
+			Grid:     []string{"  #  ", "  #  "},
+			Entrance: xy.XY{X: 2, Y: 1},
+			Start:    xy.XY{X: 0, Y: 0},
+			Goal:     xy.XY{X: 4, Y: 1},
+		}},
+	}
+
+	err := jplan.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+
+	want := []struct {
+		path     string
+		row, col int
+		kind     error
+	}{
+		{"main_map[0]", 0, 2, ErrUnknownChar},
+		{"start", 1, 9, ErrOutOfGrid},
+		{"dungeons[0].goal", 1, 4, ErrUnreachable},
+		{"master_sword", 0, 0, ErrOverlap},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d problems, got %d: %v", len(want), len(errs), err)
+	}
+	for idx, problem := range want {
+		got := errs[idx]
+		if got.Path != problem.path || got.Row != problem.row || got.Col != problem.col {
+			t.Errorf("Problem %d is at %s (%d, %d), expected %s (%d, %d)",
+				idx, got.Path, got.Row, got.Col, problem.path, problem.row, problem.col)
+		}
+		if !errors.Is(got, problem.kind) {
+			t.Errorf("Problem %d is %v, expected it to be %v", idx, got, problem.kind)
+		}
+	}
+
+	if !errors.Is(err, ErrUnreachable) {
+		t.Error("Expected errors.Is to look through every problem")
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Path != "main_map[0]" {
+		t.Error("Expected errors.As to find the first problem")
+	}
+}
+
+func TestEntranceOnImpassable(t *testing.T) {
+	impassable := false
+	jplan := JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 1, Y: 0},
+		Start:       xy.XY{X: 2, Y: 0},
+		Palette: []JSONTerrain{
+			{Char: "^", Name: "cliff", Passable: &impassable, Color: "#5a4a3a"},
+		},
+		MainMap: []string{"   ^"},
+		Dungeons: []JSONDungeon{{
+			Grid:     []string{"  "},
+			Entrance: xy.XY{X: 3, Y: 0},
+			Start:    xy.XY{X: 0, Y: 0},
+			Goal:     xy.XY{X: 1, Y: 0},
+		}},
+	}
+
+	var validationErr *ValidationError
+	err := jplan.Validate()
+	if !errors.As(err, &validationErr) || validationErr.Path != "dungeons[0].entrance" {
+		t.Fatalf("Expected the entrance to be reported, got %v", err)
+	}
+	if !errors.Is(validationErr, ErrImpassable) {
+		t.Fatalf("Expected the entrance to be on an impassable block, got %v", err)
+	}
+}
+
+func TestValidateTeleporters(t *testing.T) {
+	jplan := JSONPlan{
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 1, Y: 0},
+		Start:       xy.XY{X: 2, Y: 0},
+		Palette: []JSONTerrain{
+			{Char: "v", Name: "waterfall", Direction: "down", Color: "#5080e0"},
+		},
+		MainMap: []string{"   v"},
+		Teleporters: []JSONTeleporter{
+			{From: xy.XY{X: 3, Y: 0}, To: xy.XY{X: 4, Y: 0}},
+			{From: xy.XY{X: 2, Y: 0}, To: xy.XY{X: 3, Y: 0}, OneWay: true},
+		},
+	}
+
+	err := jplan.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	want := []struct {
+		path string
+		kind error
+	}{
+		{"palette[0].direction", ErrInvalidTerrain},
+		{"teleporters[0].to", ErrOutOfGrid},
+		{"teleporters[1].from", ErrOverlap},
+		{"teleporters[1].to", ErrOverlap},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d problems, got %d: %v", len(want), len(errs), err)
+	}
+	for idx, problem := range want {
+		if errs[idx].Path != problem.path || !errors.Is(errs[idx], problem.kind) {
+			t.Errorf("Problem %d is %v, expected %v at %s",
+				idx, errs[idx], problem.kind, problem.path)
+		}
+	}
+}
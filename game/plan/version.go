@@ -0,0 +1,148 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CurrentVersion is the version of the JSON plan format which this package writes. Older
+// versions are migrated to it when read.
+//
+// The versions are:
+//
+//  1. The original format, whose files have no "version" field. Its keys may be spelled
+//     in any case, such as a coordinate's "Y".
+//  2. Adds the "version" and "palette" fields. Coordinates are spelled in lowercase.
+//  3. Adds the "teleporters" field and the "direction" of a palette's terrains.
+const CurrentVersion = 3
+
+// ErrUnsupportedVersion is returned when a plan's version is newer than CurrentVersion,
+// or is not a version at all.
+var ErrUnsupportedVersion = errors.New("unsupported plan version")
+
+// migrations maps each version to the function which migrates a document of that
+// version to the next one. Documents are decoded into generic values, so migrations may
+// rename, move or drop fields which the current format does not know about.
+var migrations = map[int]func(doc map[string]any) error{
+	1: migrateV1,
+	2: migrateV2,
+}
+
+// LoadJSON reads a JSON plan from the file at path, which becomes the plan's Dir.
+func LoadJSON(path string) (JSONPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return JSONPlan{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	jplan, err := ParseJSON(data)
+	if err != nil {
+		return JSONPlan{}, err
+	}
+	jplan.Dir = filepath.Dir(path)
+	return jplan, nil
+}
+
+// ParseJSON parses a JSON plan of any version, which is migrated to CurrentVersion.
+// Unlike json.Unmarshal, unknown fields are reported rather than ignored, so a file of a
+// newer format is never silently misread. A "$schema" field, which refers to the plan's
+// JSON Schema, is allowed. The plan is not validated.
+func ParseJSON(data []byte) (JSONPlan, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return JSONPlan{}, fmt.Errorf("failed to decode plan: %w", err)
+	}
+	if err := migrate(doc); err != nil {
+		return JSONPlan{}, err
+	}
+	// the schema a file refers to is only meant for editors
+	delete(doc, "$schema")
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return JSONPlan{}, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(migrated))
+	decoder.DisallowUnknownFields()
+	var jplan JSONPlan
+	if err := decoder.Decode(&jplan); err != nil {
+		return JSONPlan{}, fmt.Errorf("failed to decode plan: %w", err)
+	}
+	return jplan, nil
+}
+
+// migrate migrates doc from its own version to CurrentVersion, one version at a time.
+func migrate(doc map[string]any) error {
+	version := 1
+	if value, ok := doc["version"]; ok {
+		number, isNumber := value.(float64)
+		if !isNumber || number != float64(int(number)) || number < 1 {
+			return fmt.Errorf("%w: %v", ErrUnsupportedVersion, value)
+		}
+		version = int(number)
+	}
+	if version > CurrentVersion {
+		return fmt.Errorf(
+			"%w: %d, the newest known version is %d",
+			ErrUnsupportedVersion, version, CurrentVersion,
+		)
+	}
+
+	for ; version < CurrentVersion; version++ {
+		if err := migrations[version](doc); err != nil {
+			return fmt.Errorf("failed to migrate plan from version %d: %w", version, err)
+		}
+	}
+	doc["version"] = CurrentVersion
+	return nil
+}
+
+// migrateV1 spells every key of the document in lowercase. Version 1 files were decoded
+// without regard to case, so any spelling of a known key, such as "Start" or a
+// coordinate's "Y", was accepted.
+func migrateV1(doc map[string]any) error {
+	foldKeys(doc, "master_sword", "lost_woods", "start", "main_map", "dungeons")
+	for _, key := range [...]string{"master_sword", "lost_woods", "start"} {
+		foldKeys(doc[key], "x", "y")
+	}
+	dungeons, _ := doc["dungeons"].([]any)
+	for _, dungeon := range dungeons {
+		foldKeys(dungeon, "grid", "entrance", "start", "goal")
+		if dungeon, ok := dungeon.(map[string]any); ok {
+			for _, key := range [...]string{"entrance", "start", "goal"} {
+				foldKeys(dungeon[key], "x", "y")
+			}
+		}
+	}
+	return nil
+}
+
+// foldKeys renames every key of value, which is expected to be an object, that matches
+// one of keys without regard to case to that key. A key which is already spelled as
+// given is kept over its other spellings.
+func foldKeys(value any, keys ...string) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	for found, field := range object {
+		for _, key := range keys {
+			if found == key || !strings.EqualFold(found, key) {
+				continue
+			}
+			delete(object, found)
+			if _, isSet := object[key]; !isSet {
+				object[key] = field
+			}
+		}
+	}
+}
+
+// migrateV2 leaves the document as it is, as version 3 only adds optional fields.
+func migrateV2(map[string]any) error {
+	return nil
+}
@@ -0,0 +1,126 @@
+package plan
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestParseJSONMigratesVersion1(t *testing.T) {
+	data := `{
+		"master_sword": {"x": 0, "Y": 0},
+		"lost_woods": {"x": 1, "Y": 0},
+		"start": {"x": 2, "Y": 1},
+		"main_map": ["@  ", "   "],
+		"dungeons": [{
+			"grid": ["  "],
+			"entrance": {"x": 0, "Y": 1},
+			"start": {"x": 0, "Y": 0},
+			"goal": {"x": 1, "Y": 0}
+		}]
+	}`
+	jplan, err := ParseJSON([]byte(data))
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	want := JSONPlan{
+		Version:     CurrentVersion,
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 1, Y: 0},
+		Start:       xy.XY{X: 2, Y: 1},
+		MainMap:     []string{"@  ", "   "},
+		Dungeons: []JSONDungeon{{
+			Grid:     []string{"  "},
+			Entrance: xy.XY{X: 0, Y: 1},
+			Start:    xy.XY{X: 0, Y: 0},
+			Goal:     xy.XY{X: 1, Y: 0},
+		}},
+	}
+	if !reflect.DeepEqual(jplan, want) {
+		t.Errorf("Unexpected plan: %+v", jplan)
+	}
+	if err := jplan.Validate(); err != nil {
+		t.Error("Unexpected error:", err)
+	}
+}
+
+func TestLoadJSONMigratesMixedCaseVersion1(t *testing.T) {
+	jplan, err := LoadJSON("testdata/v1_mixed_case.json")
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	want := JSONPlan{
+		Version:     CurrentVersion,
+		MasterSword: xy.XY{X: 0, Y: 0},
+		LostWoods:   xy.XY{X: 1, Y: 0},
+		Start:       xy.XY{X: 2, Y: 1},
+		MainMap:     []string{"@  ", "   "},
+		Dungeons: []JSONDungeon{{
+			Grid:     []string{"  "},
+			Entrance: xy.XY{X: 0, Y: 1},
+			Start:    xy.XY{X: 0, Y: 0},
+			Goal:     xy.XY{X: 1, Y: 0},
+		}},
+		Dir: "testdata",
+	}
+	if !reflect.DeepEqual(jplan, want) {
+		t.Errorf("Unexpected plan: %+v", jplan)
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	cases := map[string]error{
+		`{"version": 4, "main_map": []}`:   ErrUnsupportedVersion,
+		`{"version": 1.5, "main_map": []}`: ErrUnsupportedVersion,
+		`{"version": "2", "main_map": []}`: ErrUnsupportedVersion,
+		`{"version": 0, "main_map": []}`:   ErrUnsupportedVersion,
+	}
+	for data, want := range cases {
+		if _, err := ParseJSON([]byte(data)); !errors.Is(err, want) {
+			t.Errorf("%s: expected %v, got %v", data, want, err)
+		}
+	}
+
+	_, err := ParseJSON([]byte(`{"version": 3, "main_map": [], "portals": []}`))
+	if err == nil || !strings.Contains(err.Error(), "portals") {
+		t.Errorf("Expected the unknown field to be reported, got %v", err)
+	}
+	// only version 1 spells Y in uppercase, so current plans must use lowercase
+	_, err = ParseJSON([]byte(`{"version": 3, "start": {"x": 0, "Y": 1}, "main_map": []}`))
+	if err == nil || !strings.Contains(err.Error(), `"Y"`) {
+		t.Errorf("Expected the uppercase key to be reported, got %v", err)
+	}
+	if _, err := ParseJSON([]byte(`[]`)); err == nil {
+		t.Error("Expected an error for a plan which is not an object")
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	data, err := FormatJSON(DefaultJSONPlan())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	// files may refer to the schema
+	data = []byte(strings.Replace(string(data), "{", `{"$schema": "plan.schema.json",`, 1))
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	jplan, err := LoadJSON(path)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if jplan.Dir != filepath.Dir(path) {
+		t.Errorf("Unexpected dir: %s", jplan.Dir)
+	}
+	jplan.Dir = ""
+	if !reflect.DeepEqual(jplan, DefaultJSONPlan()) {
+		t.Error("Loaded plan differs from the saved one")
+	}
+}
@@ -0,0 +1,197 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png"
+	"os"
+
+	"github.com/agstrc/heuristic-search/game/plan"
+	"github.com/agstrc/heuristic-search/images"
+	"github.com/agstrc/heuristic-search/xy"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// This file maps the plan's terrains, which know nothing about rendering, to the images
+// they are drawn with.
+
+// tileset maps each terrain of a plan to its image.
+type tileset map[plan.Terrain]*ebiten.Image
+
+// predefinedImages maps the name of each predefined terrain to its image.
+var predefinedImages = map[string]*ebiten.Image{
+	plan.Forest.Name():   images.Forest,
+	plan.Grass.Name():    images.Grass,
+	plan.Mountain.Name(): images.Mountain,
+	plan.Sand.Name():     images.Sand,
+	plan.Water.Name():    images.Water,
+}
+
+// newTileset loads the image of every terrain of terrains. A terrain is drawn with its
+// image file or its color if it has any; otherwise, it is drawn with the image of the
+// predefined terrain of the same name. One-way terrains are marked by a dark band on the
+// side they lead to.
+func newTileset(terrains [][]plan.Terrain) (tileset, error) {
+	tiles := make(tileset)
+	for _, row := range terrains {
+		for _, terrain := range row {
+			if _, isLoaded := tiles[terrain]; isLoaded {
+				continue
+			}
+
+			tile, err := terrainImage(terrain)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load image of terrain %q: %w", terrain.Name(), err)
+			}
+			if direction, isOneWay := terrain.Direction(); isOneWay {
+				// the image may be shared by other terrains, so the band is drawn on a copy
+				marked := ebiten.NewImage(plan.TIS, plan.TIS)
+				marked.DrawImage(tile, nil)
+				fillRect(marked, edgeBand(direction), bandColor)
+				tile = marked
+			}
+			tiles[terrain] = tile
+		}
+	}
+	return tiles, nil
+}
+
+func terrainImage(terrain plan.Terrain) (*ebiten.Image, error) {
+	if path := terrain.Image(); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		decoded, _, err := image.Decode(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		return ebiten.NewImageFromImage(decoded), nil
+	}
+
+	if fill, ok := terrain.Color(); ok {
+		tile := ebiten.NewImage(plan.TIS, plan.TIS)
+		tile.Fill(fill)
+		return tile, nil
+	}
+
+	if tile, ok := predefinedImages[terrain.Name()]; ok {
+		return tile, nil
+	}
+	return nil, fmt.Errorf("terrain has neither an image nor a color")
+}
+
+// keyColors are the colors of the keys, and of the doors they open, indexed by key.
+var keyColors = [plan.MaxKeys]color.RGBA{
+	{0xe0, 0xc0, 0x20, 0xff}, {0x30, 0x90, 0xe0, 0xff}, {0xd0, 0x30, 0x30, 0xff},
+	{0x30, 0xb0, 0x50, 0xff}, {0xa0, 0x50, 0xd0, 0xff}, {0xe0, 0x80, 0x20, 0xff},
+	{0x20, 0xc0, 0xc0, 0xff}, {0xe0, 0x60, 0xa0, 0xff}, {0x80, 0x60, 0x30, 0xff},
+	{0xc0, 0xc0, 0xc0, 0xff},
+}
+
+// dungeonImages caches the images of the dungeon terrains which are drawn over a floor,
+// as they are drawn every frame.
+var dungeonImages = make(map[plan.DungeonTerrain]*ebiten.Image)
+
+// dungeonTerrainImage returns the image of a dungeon terrain. Keys, doors, traps and
+// ledges are drawn over a floor: keys as small squares and doors as large ones of the
+// key's color, traps as red crosses and ledges as dark bands on the side they point to.
+func dungeonTerrainImage(terrain plan.DungeonTerrain) *ebiten.Image {
+	switch terrain {
+	case plan.Traversable:
+		return images.Traversable
+	case plan.NonTraversable:
+		return images.NonTraversable
+	}
+	if tile, ok := dungeonImages[terrain]; ok {
+		return tile
+	}
+
+	tile := ebiten.NewImage(plan.TIS, plan.TIS)
+	tile.DrawImage(images.Traversable, nil)
+	fill := func(rect image.Rectangle, fill color.Color) {
+		fillRect(tile, rect, fill)
+	}
+
+	const third = plan.TIS / 3
+	if key, isKey := terrain.Key(); isKey {
+		fill(image.Rect(third, third, plan.TIS-third, plan.TIS-third), keyColors[key])
+	}
+	if key, isDoor := terrain.Door(); isDoor {
+		fill(image.Rect(2, 2, plan.TIS-2, plan.TIS-2), keyColors[key])
+		fill(image.Rect(plan.TIS/2-2, third, plan.TIS/2+2, plan.TIS-third), color.Black)
+	}
+	if terrain == plan.Trap {
+		red := color.RGBA{0xc0, 0x20, 0x20, 0xff}
+		for i := 4; i < plan.TIS-4; i++ {
+			fill(image.Rect(i, i, i+2, i+1), red)
+			fill(image.Rect(plan.TIS-i-2, i, plan.TIS-i, i+1), red)
+		}
+	}
+	if direction, isLedge := terrain.Ledge(); isLedge {
+		fill(edgeBand(direction), bandColor)
+	}
+
+	dungeonImages[terrain] = tile
+	return tile
+}
+
+// bandColor is the color of the bands which mark one-way terrains and ledges.
+var bandColor = color.RGBA{0x40, 0x30, 0x20, 0xff}
+
+// edgeBand returns the band along the edge of a tile which direction points to.
+func edgeBand(direction xy.XY) image.Rectangle {
+	const width = plan.TIS / 6
+	band := image.Rect(0, 0, plan.TIS, plan.TIS)
+	switch direction {
+	case xy.XY{X: 1}:
+		band.Min.X = plan.TIS - width
+	case xy.XY{X: -1}:
+		band.Max.X = width
+	case xy.XY{Y: 1}:
+		band.Min.Y = plan.TIS - width
+	case xy.XY{Y: -1}:
+		band.Max.Y = width
+	}
+	return band
+}
+
+func fillRect(tile *ebiten.Image, rect image.Rectangle, fill color.Color) {
+	tile.SubImage(rect).(*ebiten.Image).Fill(fill)
+}
+
+// teleporterImages caches the images of teleporters, indexed by whether they are the
+// arrival of a one-way warp.
+var teleporterImages [2]*ebiten.Image
+
+// teleporterImage returns the image of a teleporter's end, which is a violet ring. The
+// arrival of a one-way warp, which may not be taken back, has a pale ring instead.
+func teleporterImage(arrival bool) *ebiten.Image {
+	index := 0
+	if arrival {
+		index = 1
+	}
+	if teleporterImages[index] != nil {
+		return teleporterImages[index]
+	}
+
+	ring := color.RGBA{0x90, 0x40, 0xd0, 0xff}
+	if arrival {
+		ring = color.RGBA{0xd0, 0xb0, 0xf0, 0xff}
+	}
+	tile := ebiten.NewImage(plan.TIS, plan.TIS)
+	fillRect(tile, image.Rect(6, 6, plan.TIS-6, plan.TIS-6), ring)
+	tile.SubImage(image.Rect(10, 10, plan.TIS-10, plan.TIS-10)).(*ebiten.Image).Clear()
+
+	teleporterImages[index] = tile
+	return tile
+}
+
+// virtueImage returns the image of the goal of the dungeon at index.
+func virtueImage(index int) *ebiten.Image {
+	virtues := [...]*ebiten.Image{images.Virtue1, images.Virtue2, images.Virtue3}
+	return virtues[index%len(virtues)]
+}
@@ -0,0 +1,84 @@
+// Package grid provides a generic two-dimensional grid and an implementation of
+// astar.Node on top of it.
+package grid
+
+import "github.com/agstrc/heuristic-search/xy"
+
+// Grid is a rectangular grid of values of type T. Its rows are indexed by Y and its
+// columns by X.
+type Grid[T any] struct {
+	width, height int
+	cells         [][]T
+}
+
+// New returns a grid with the given dimensions, filled with the zero value of T.
+func New[T any](width, height int) *Grid[T] {
+	cells := make([][]T, height)
+	for y := range cells {
+		cells[y] = make([]T, width)
+	}
+	return &Grid[T]{width: width, height: height, cells: cells}
+}
+
+// FromRows returns a grid backed by rows, which are not copied. Every row must have the
+// same length as the first one, otherwise FromRows panics.
+func FromRows[T any](rows [][]T) *Grid[T] {
+	width := 0
+	if len(rows) > 0 {
+		width = len(rows[0])
+	}
+	for _, row := range rows {
+		if len(row) != width {
+			panic("grid rows must have the same length")
+		}
+	}
+	return &Grid[T]{width: width, height: len(rows), cells: rows}
+}
+
+// Width returns the grid's amount of columns.
+func (g *Grid[T]) Width() int {
+	return g.width
+}
+
+// Height returns the grid's amount of rows.
+func (g *Grid[T]) Height() int {
+	return g.height
+}
+
+// In reports whether at is within the grid's bounds.
+func (g *Grid[T]) In(at xy.XY) bool {
+	return g.Bounds().Contains(at)
+}
+
+// Bounds returns the rectangle which contains every cell of the grid.
+func (g *Grid[T]) Bounds() xy.Rect {
+	return xy.RectOf(g.width, g.height)
+}
+
+// At returns the value at the given coordinates. It panics if at is out of bounds.
+func (g *Grid[T]) At(at xy.XY) T {
+	return g.cells[at.Y][at.X]
+}
+
+// Set sets the value at the given coordinates. It panics if at is out of bounds.
+func (g *Grid[T]) Set(at xy.XY, value T) {
+	g.cells[at.Y][at.X] = value
+}
+
+// Rows returns the grid's rows. They are not copied, so changes to them are reflected
+// on the grid.
+func (g *Grid[T]) Rows() [][]T {
+	return g.cells
+}
+
+// Each calls f for every cell of the grid, row by row. If f returns false, the
+// iteration stops.
+func (g *Grid[T]) Each(f func(at xy.XY, value T) bool) {
+	for y, row := range g.cells {
+		for x, value := range row {
+			if !f(xy.XY{X: x, Y: y}, value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package grid
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/astar/verify"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestGrid(t *testing.T) {
+	grid := New[int](3, 2)
+	if grid.Width() != 3 || grid.Height() != 2 {
+		t.Fatalf("Unexpected dimensions: %dx%d", grid.Width(), grid.Height())
+	}
+
+	for _, at := range [...]xy.XY{{X: -1, Y: 0}, {X: 3, Y: 0}, {X: 0, Y: 2}} {
+		if grid.In(at) {
+			t.Fatalf("Expected %v to be out of bounds", at)
+		}
+	}
+
+	grid.Set(xy.XY{X: 2, Y: 1}, 5)
+	if grid.At(xy.XY{X: 2, Y: 1}) != 5 {
+		t.Fatal("Expected set value to be returned")
+	}
+	if !reflect.DeepEqual(grid.Rows(), [][]int{{0, 0, 0}, {0, 0, 5}}) {
+		t.Fatal("Rows differ from expected")
+	}
+
+	var visited []xy.XY
+	grid.Each(func(at xy.XY, value int) bool {
+		visited = append(visited, at)
+		return value == 0
+	})
+	if len(visited) != 6 || visited[5] != (xy.XY{X: 2, Y: 1}) {
+		t.Fatal("Expected every cell to be visited row by row")
+	}
+}
+
+func TestNode(t *testing.T) {
+	// 0 represents a wall
+	graph := &Graph[int]{
+		Grid: FromRows([][]int{
+			{1, 1, 1},
+			{0, 0, 1},
+			{5, 1, 1},
+		}),
+		Passable: func(cost int) bool { return cost > 0 },
+		Cost:     func(cost int) int { return cost },
+	}
+
+	neighbors := graph.Node(xy.XY{X: 2, Y: 1}).Neighbors()
+	want := []Node[int]{graph.Node(xy.XY{X: 2, Y: 0}), graph.Node(xy.XY{X: 2, Y: 2})}
+	if !reflect.DeepEqual(neighbors, want) {
+		t.Fatal("Neighbors differ from expected")
+	}
+
+	heuristic := Manhattan[int](1)
+	start, goal := graph.Node(xy.XY{X: 0, Y: 0}), graph.Node(xy.XY{X: 0, Y: 2})
+	path, cost := astar.FindPath(start, goal, heuristic)
+	if cost != 10 || len(path) != 7 {
+		t.Fatalf("Unexpected path with cost %d and length %d", cost, len(path))
+	}
+
+	verify.Heuristic(t, heuristic, start)
+}
+
+func TestEightConnected(t *testing.T) {
+	// 0 represents a wall
+	rows := [][]int{
+		{10, 0, 10},
+		{10, 10, 10},
+		{20, 10, 0},
+	}
+	newGraph := func(corners CornerRule, diagonal DiagonalCost) *Graph[int] {
+		return &Graph[int]{
+			Grid:         FromRows(rows),
+			Passable:     func(cost int) bool { return cost > 0 },
+			Cost:         func(cost int) int { return cost },
+			Neighborhood: EightConnected,
+			Diagonal:     diagonal,
+			Corners:      corners,
+		}
+	}
+
+	for _, test := range []struct {
+		corners CornerRule
+		from    xy.XY
+		count   int
+	}{
+		// (0, 1) reaches (1, 2) diagonally, but not (1, 0), which is a wall
+		{CornersNever, xy.XY{X: 0, Y: 1}, 4},
+		// (1, 1) reaches (0, 0) and (2, 0) diagonally only by cutting a corner
+		{CornersNever, xy.XY{X: 1, Y: 1}, 4},
+		{CornersIfOneFree, xy.XY{X: 1, Y: 1}, 6},
+		{CornersAlways, xy.XY{X: 1, Y: 1}, 6},
+		// (2, 1) reaches (1, 2) diagonally only by cutting a corner
+		{CornersNever, xy.XY{X: 2, Y: 1}, 2},
+		{CornersIfOneFree, xy.XY{X: 2, Y: 1}, 3},
+	} {
+		graph := newGraph(test.corners, DiagonalOctile)
+		neighbors := graph.Node(test.from).Neighbors()
+		if len(neighbors) != test.count {
+			t.Fatalf(
+				"Expected %d neighbors from %v with rule %d, got %d",
+				test.count, test.from, test.corners, len(neighbors),
+			)
+		}
+	}
+
+	t.Run("cost", func(t *testing.T) {
+		from := xy.XY{X: 1, Y: 1}
+		to := xy.XY{X: 0, Y: 2}
+		for diagonal, want := range map[DiagonalCost]int{DiagonalOctile: 28, DiagonalSqrt2: 28} {
+			graph := newGraph(CornersAlways, diagonal)
+			if cost := graph.Node(from).StepCost(graph.Node(to)); cost != want {
+				t.Fatalf("Expected diagonal cost %d, got %d", want, cost)
+			}
+		}
+		if DiagonalSqrt2.Scale(10) != 14 || DiagonalOctile.Scale(15) != 21 || DiagonalSqrt2.Scale(15) != 21 {
+			t.Fatal("Unexpected diagonal scaling")
+		}
+		if DiagonalOctile.Scale(5) != 7 || DiagonalSqrt2.Scale(5) != 7 || DiagonalSqrt2.Scale(100) != 141 {
+			t.Fatal("Unexpected diagonal scaling")
+		}
+	})
+
+	t.Run("heuristics", func(t *testing.T) {
+		for _, corners := range [...]CornerRule{CornersNever, CornersIfOneFree, CornersAlways} {
+			graph := newGraph(corners, DiagonalSqrt2)
+			start := graph.Node(xy.XY{X: 0, Y: 0})
+
+			verify.Heuristic(t, Octile[int](10, DiagonalSqrt2), start)
+			verify.Heuristic(t, Chebyshev[int](10), start)
+		}
+
+		graph := newGraph(CornersAlways, DiagonalOctile)
+		start, goal := graph.Node(xy.XY{X: 0, Y: 0}), graph.Node(xy.XY{X: 2, Y: 0})
+		path, cost := astar.FindPath(start, goal, Octile[int](10, DiagonalOctile))
+		// the wall's corner is cut twice
+		if cost != 28 || len(path) != 3 {
+			t.Fatalf("Unexpected path with cost %d and length %d", cost, len(path))
+		}
+	})
+}
+
+func TestHexagonal(t *testing.T) {
+	graph := &Graph[int]{
+		Grid: FromRows([][]int{
+			{1, 1, 1},
+			{1, 1, 0},
+			{1, 1, 1},
+		}),
+		Passable:     func(cost int) bool { return cost > 0 },
+		Cost:         func(cost int) int { return cost },
+		Neighborhood: Hexagonal,
+	}
+
+	// (1, 1) is on an odd row, so it touches (1, 0), (2, 0), (1, 2) and (2, 2) besides
+	// (0, 1) and (2, 1), which is a wall
+	if neighbors := graph.Node(xy.XY{X: 1, Y: 1}).Neighbors(); len(neighbors) != 5 {
+		t.Fatalf("Expected 5 neighbors, got %d", len(neighbors))
+	}
+	// (0, 0) is on an even row, so it touches (0, 1) but not (1, 1)
+	neighbors := graph.Node(xy.XY{X: 0, Y: 0}).Neighbors()
+	want := []Node[int]{graph.Node(xy.XY{X: 1, Y: 0}), graph.Node(xy.XY{X: 0, Y: 1})}
+	if !reflect.DeepEqual(neighbors, want) {
+		t.Fatalf("Neighbors differ from expected: %v", neighbors)
+	}
+
+	heuristic := Hex[int](1)
+	start := graph.Node(xy.XY{X: 0, Y: 0})
+	path, cost := astar.FindPath(start, graph.Node(xy.XY{X: 2, Y: 2}), heuristic)
+	if cost != 3 || len(path) != 4 {
+		t.Fatalf("Unexpected path with cost %d and length %d", cost, len(path))
+	}
+	verify.Heuristic(t, heuristic, start)
+}
+
+func TestReachable(t *testing.T) {
+	// 0 represents a wall
+	graph := &Graph[int]{
+		Grid: FromRows([][]int{
+			{1, 0, 1},
+			{1, 0, 1},
+			{1, 0, 1},
+		}),
+		Passable: func(cost int) bool { return cost > 0 },
+		Cost:     func(cost int) int { return cost },
+	}
+
+	reached := graph.Reachable(xy.XY{X: 0, Y: 1})
+	if len(reached) != 3 {
+		t.Fatalf("Expected 3 reachable cells, got %d", len(reached))
+	}
+	if _, ok := reached[xy.XY{X: 2, Y: 0}]; ok {
+		t.Fatal("Cells behind the wall should not be reachable")
+	}
+	if len(graph.Reachable(xy.XY{X: 1, Y: 1})) != 0 {
+		t.Fatal("Nothing should be reachable from a wall")
+	}
+
+	labels, count := graph.Components()
+	if count != 2 {
+		t.Fatalf("Expected 2 components, got %d", count)
+	}
+	want := [][]int{{0, -1, 1}, {0, -1, 1}, {0, -1, 1}}
+	if !reflect.DeepEqual(labels.Rows(), want) {
+		t.Fatalf("Unexpected components: %v", labels.Rows())
+	}
+
+	graph.Neighborhood = EightConnected
+	graph.Corners = CornersAlways
+	if len(graph.Reachable(xy.XY{X: 0, Y: 1})) != 3 {
+		t.Fatal("Diagonal moves should not jump over the wall")
+	}
+}
+
+func TestEnterable(t *testing.T) {
+	// 2 may only be entered moving down
+	graph := &Graph[int]{
+		Grid: FromRows([][]int{
+			{1, 1, 1},
+			{1, 2, 1},
+			{1, 1, 1},
+		}),
+		Cost: func(cost int) int { return cost },
+		Enterable: func(cell int, step xy.XY) bool {
+			return cell != 2 || step == xy.XY{X: 0, Y: 1}
+		},
+	}
+
+	neighbors := graph.Node(xy.XY{X: 1, Y: 2}).Neighbors()
+	for _, neighbor := range neighbors {
+		if neighbor.XY == (xy.XY{X: 1, Y: 1}) {
+			t.Fatal("Expected the one-way cell not to be entered moving up")
+		}
+	}
+	if len(graph.Node(xy.XY{X: 1, Y: 0}).Neighbors()) != 3 {
+		t.Fatal("Expected the one-way cell to be entered moving down")
+	}
+
+	top, bottom := graph.Node(xy.XY{X: 1, Y: 0}), graph.Node(xy.XY{X: 1, Y: 2})
+	down, _ := astar.FindPath(top, bottom, Manhattan[int](1))
+	up, _ := astar.FindPath(bottom, top, Manhattan[int](1))
+	if len(down) != 3 || len(up) != 5 {
+		t.Fatalf("Expected paths of 3 and 5 cells, got %d and %d", len(down), len(up))
+	}
+}
@@ -0,0 +1,45 @@
+package grid
+
+import (
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// The heuristics in this file are scaled by minCost. As long as minCost is the cost of
+// the graph's cheapest cell, they never overestimate the cost of a path.
+
+// Manhattan returns a heuristic which computes the Manhattan distance between two nodes.
+// It is meant for FourConnected graphs.
+func Manhattan[T any](minCost int) astar.Heuristic[Node[T]] {
+	return func(from, to Node[T]) int {
+		return xy.Manhattan(from.XY, to.XY) * minCost
+	}
+}
+
+// Octile returns a heuristic which computes the octile distance between two nodes, which
+// is the cost of the cheapest path on an empty EightConnected graph. diagonal must be
+// the graph's diagonal cost.
+func Octile[T any](minCost int, diagonal DiagonalCost) astar.Heuristic[Node[T]] {
+	return func(from, to Node[T]) int {
+		diagonalSteps := xy.Manhattan(from.XY, to.XY) - xy.Chebyshev(from.XY, to.XY)
+		straightSteps := xy.Chebyshev(from.XY, to.XY) - diagonalSteps
+		return straightSteps*minCost + diagonalSteps*diagonal.Scale(minCost)
+	}
+}
+
+// Chebyshev returns a heuristic which computes the Chebyshev distance between two nodes,
+// as if diagonal moves cost as much as orthogonal ones. It is meant for EightConnected
+// graphs.
+func Chebyshev[T any](minCost int) astar.Heuristic[Node[T]] {
+	return func(from, to Node[T]) int {
+		return xy.Chebyshev(from.XY, to.XY) * minCost
+	}
+}
+
+// Hex returns a heuristic which computes the amount of steps between two nodes of a
+// Hexagonal graph.
+func Hex[T any](minCost int) astar.Heuristic[Node[T]] {
+	return func(from, to Node[T]) int {
+		return xy.HexDistance(xy.OddRToAxial(from.XY), xy.OddRToAxial(to.XY)) * minCost
+	}
+}
@@ -0,0 +1,78 @@
+package grid
+
+import (
+	"math"
+
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// Neighborhood defines which cells are connected to a cell.
+type Neighborhood int
+
+const (
+	// FourConnected connects a cell to the cells above, below and to its sides.
+	FourConnected Neighborhood = iota
+	// EightConnected connects a cell to the same cells as FourConnected plus the four
+	// diagonal ones.
+	EightConnected
+	// Hexagonal treats cells as pointy-top hexagons in the odd-r offset layout, in
+	// which odd rows are shoved half a cell to the right. Each cell is connected to the
+	// six cells around it.
+	Hexagonal
+)
+
+// DiagonalCost defines how the cost of moving diagonally into a cell is derived from
+// the cell's cost.
+type DiagonalCost int
+
+const (
+	// DiagonalOctile multiplies the cell's cost by 14/10, rounding down.
+	DiagonalOctile DiagonalCost = iota
+	// DiagonalSqrt2 multiplies the cell's cost by √2, rounding to the nearest integer.
+	DiagonalSqrt2
+)
+
+// Scale returns the cost of moving diagonally into a cell which costs cost.
+func (dc DiagonalCost) Scale(cost int) int {
+	if dc == DiagonalSqrt2 {
+		return int(math.Round(float64(cost) * math.Sqrt2))
+	}
+	return cost * 14 / 10
+}
+
+// CornerRule defines whether a diagonal move may cut past the corner of an impassable
+// cell. The cells checked are the two orthogonal neighbors shared by both ends of the
+// move.
+type CornerRule int
+
+const (
+	// CornersNever never lets a diagonal move cut a corner, so it is only allowed if
+	// both orthogonal cells are passable.
+	CornersNever CornerRule = iota
+	// CornersIfOneFree allows a diagonal move if at least one of the orthogonal cells
+	// is passable. A move may cut a corner, but never squeeze between two blocked
+	// cells.
+	CornersIfOneFree
+	// CornersAlways allows every diagonal move between passable cells.
+	CornersAlways
+)
+
+// diagonalAllowed reports whether a diagonal move from from to to is allowed by the
+// graph's corner rule. Both cells must be passable.
+func (g *Graph[T]) diagonalAllowed(from, to xy.XY) bool {
+	freeCount := 0
+	for _, corner := range [...]xy.XY{{X: from.X, Y: to.Y}, {X: to.X, Y: from.Y}} {
+		if g.passable(corner) {
+			freeCount++
+		}
+	}
+
+	switch g.Corners {
+	case CornersAlways:
+		return true
+	case CornersIfOneFree:
+		return freeCount >= 1
+	default:
+		return freeCount == 2
+	}
+}
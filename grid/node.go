@@ -0,0 +1,96 @@
+package grid
+
+import "github.com/agstrc/heuristic-search/xy"
+
+// Graph is the graph formed by the passable cells of a grid. By default, every cell is
+// connected to the cells above, below and to its sides.
+type Graph[T any] struct {
+	Grid *Grid[T]
+	// Passable reports whether a cell may be traversed. If nil, every cell is passable.
+	Passable func(T) bool
+	// Cost returns the cost to move into a cell.
+	Cost func(T) int
+	// Enterable reports whether a passable cell may be entered by a move of the given
+	// offset, which makes moves one-way. If nil, cells may be entered from any side.
+	Enterable func(cell T, step xy.XY) bool
+
+	// Neighborhood defines which cells are connected to each other. Diagonal and
+	// Corners are only taken into account by EightConnected graphs.
+	Neighborhood Neighborhood
+	// Diagonal defines the cost of moving diagonally into a cell.
+	Diagonal DiagonalCost
+	// Corners defines whether diagonal moves may cut corners.
+	Corners CornerRule
+}
+
+// Node returns the node at the given coordinates.
+func (g *Graph[T]) Node(at xy.XY) Node[T] {
+	return Node[T]{XY: at, graph: g}
+}
+
+func (g *Graph[T]) passable(at xy.XY) bool {
+	return g.Grid.In(at) && (g.Passable == nil || g.Passable(g.Grid.At(at)))
+}
+
+// enterable reports whether the cell at "to" is passable and may be entered from "from".
+func (g *Graph[T]) enterable(from, to xy.XY) bool {
+	if !g.passable(to) {
+		return false
+	}
+	return g.Enterable == nil || g.Enterable(g.Grid.At(to), to.Sub(from))
+}
+
+// Node implements astar.Node on a cell of a Graph. Nodes are only comparable to nodes of
+// the same graph.
+type Node[T any] struct {
+	xy.XY
+	// graph uses a pointer in order to make this struct comparable.
+	graph *Graph[T]
+}
+
+// Neighbors returns the node's passable neighbors which may be entered from it, in
+// accordance to the graph's neighborhood and corner rule.
+func (n Node[T]) Neighbors() []Node[T] {
+	graph := n.graph
+	neighbors := make([]Node[T], 0, 8)
+	if graph.Neighborhood == Hexagonal {
+		for _, axial := range xy.OddRToAxial(n.XY).Neighbors() {
+			if at := axial.OddR(); graph.enterable(n.XY, at) {
+				neighbors = append(neighbors, graph.Node(at))
+			}
+		}
+		return neighbors
+	}
+
+	for _, at := range n.Neighbors4() {
+		if graph.enterable(n.XY, at) {
+			neighbors = append(neighbors, graph.Node(at))
+		}
+	}
+	if graph.Neighborhood != EightConnected {
+		return neighbors
+	}
+
+	for _, direction := range xy.Directions8 {
+		at := n.Add(direction.Offset())
+		if direction.Diagonal() && graph.enterable(n.XY, at) &&
+			graph.diagonalAllowed(n.XY, at) {
+			neighbors = append(neighbors, graph.Node(at))
+		}
+	}
+	return neighbors
+}
+
+// Cost returns the cost to move orthogonally into the node.
+func (n Node[T]) Cost() int {
+	return n.graph.Cost(n.graph.Grid.At(n.XY))
+}
+
+// StepCost returns the cost to move from the node into next, which must be one of its
+// neighbors. Diagonal moves are scaled by the graph's diagonal cost.
+func (n Node[T]) StepCost(next Node[T]) int {
+	if n.graph.Neighborhood == EightConnected && n.X != next.X && n.Y != next.Y {
+		return n.graph.Diagonal.Scale(next.Cost())
+	}
+	return next.Cost()
+}
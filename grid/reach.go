@@ -0,0 +1,65 @@
+package grid
+
+import "github.com/agstrc/heuristic-search/xy"
+
+// Reachable returns the set of cells which may be reached by walking the graph from the
+// cell at "from", including itself. If "from" is not passable, the set is empty.
+func (g *Graph[T]) Reachable(from xy.XY) map[xy.XY]struct{} {
+	reached := make(map[xy.XY]struct{})
+	if !g.passable(from) {
+		return reached
+	}
+
+	reached[from] = struct{}{}
+	queue := []Node[T]{g.Node(from)}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range node.Neighbors() {
+			if _, isReached := reached[next.XY]; !isReached {
+				reached[next.XY] = struct{}{}
+				queue = append(queue, next)
+			}
+		}
+	}
+	return reached
+}
+
+// Components labels each cell of the graph with the connected component it belongs to.
+// Components are numbered from zero in the order in which their first cells appear,
+// row by row, and cells which are not passable are labeled -1. One-way moves are only
+// followed in their direction, so a component is made of the cells reached from its
+// first cell which no earlier component reached. The amount of components
+// is returned along with the labels.
+func (g *Graph[T]) Components() (*Grid[int], int) {
+	labels := New[int](g.Grid.Width(), g.Grid.Height())
+	labels.Each(func(at xy.XY, _ int) bool {
+		labels.Set(at, -1)
+		return true
+	})
+
+	count := 0
+	g.Grid.Each(func(at xy.XY, _ T) bool {
+		if !g.passable(at) || labels.At(at) != -1 {
+			return true
+		}
+
+		labels.Set(at, count)
+		queue := []Node[T]{g.Node(at)}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+
+			for _, next := range node.Neighbors() {
+				if labels.At(next.XY) == -1 {
+					labels.Set(next.XY, count)
+					queue = append(queue, next)
+				}
+			}
+		}
+		count++
+		return true
+	})
+	return labels, count
+}
@@ -1,27 +1,64 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/agstrc/heuristic-search/game"
+	"github.com/agstrc/heuristic-search/movingai"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func main() {
+	hex := flag.Bool("hex", false, "play on a hexagonal map")
+	generated := flag.Bool("generate", false, "play on a procedurally generated map")
+	seed := flag.Int64("seed", 0, "seed of the generated map; a random one if 0")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) > 0 && filepath.Ext(args[0]) == ".scen" {
+		os.Exit(runScenarios(args[0]))
+	}
+
 	ebiten.SetWindowSize(900, 900)
 	ebiten.SetWindowTitle("A*")
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 
-	args := os.Args
 	var g *game.Game
-	if len(args) > 1 {
+	if len(args) > 0 {
 		var err error
-		g, err = game.GameFromJSON(args[1])
+		switch {
+		case *hex:
+			g, err = game.HexGameFromJSON(args[0])
+		case filepath.Ext(args[0]) == ".txt":
+			g, err = game.GameFromText(args[0])
+		case filepath.Ext(args[0]) == ".tmx" || filepath.Ext(args[0]) == ".tmj":
+			g, err = game.GameFromTiled(args[0])
+		case filepath.Ext(args[0]) == ".png":
+			g, err = game.GameFromImage(args[0])
+		default:
+			g, err = game.GameFromJSON(args[0])
+		}
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Failed to create a game from JSON file:", err)
+			fmt.Fprintln(os.Stderr, "Failed to create a game from file:", err)
+			os.Exit(1)
+		}
+	} else if *generated {
+		if *seed == 0 {
+			*seed = time.Now().UnixNano()
+		}
+		fmt.Println("Generating a map from seed", *seed)
+
+		var err error
+		if g, err = game.GeneratedGame(*seed); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to generate a game:", err)
 			os.Exit(1)
 		}
+	} else if *hex {
+		g = game.DefaultHexGame()
 	} else {
 		g = game.DefaultGame()
 	}
@@ -31,3 +68,30 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runScenarios runs a Moving AI scenario file, reporting the scenarios whose paths do not
+// match their optimal lengths. It returns the process' exit code.
+func runScenarios(path string) int {
+	results, err := movingai.RunFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to run scenarios:", err)
+		return 1
+	}
+
+	failed := 0
+	for idx, result := range results {
+		if result.OK() {
+			continue
+		}
+		failed++
+		fmt.Printf(
+			"Scenario %d (%v -> %v): expected length %.8f, got %.8f\n", idx,
+			result.Scenario.Start, result.Scenario.Goal, result.Scenario.Optimal, result.Length,
+		)
+	}
+	fmt.Printf("%d of %d scenarios solved optimally\n", len(results)-failed, len(results))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
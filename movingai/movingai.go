@@ -0,0 +1,158 @@
+// Package movingai reads the benchmark maps and scenarios of the Moving AI Lab
+// (https://movingai.com/benchmarks), so the A* implementation may be compared against
+// the optimal path lengths found in the literature.
+//
+// Benchmark maps are octile grids: every cell is connected to its eight neighbors,
+// diagonal moves cost √2 and may never cut the corner of an impassable cell. Only '.',
+// 'G' and 'S' cells are passable. As costs are integers, every move is scaled by
+// StraightCost.
+package movingai
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// StraightCost is the cost of an orthogonal move. The cost of a diagonal move is
+// StraightCost * √2, rounded to the nearest integer.
+const StraightCost = 1_000_000
+
+// Map is a benchmark map.
+type Map struct {
+	// Rows holds the map's cells, one character per cell.
+	Rows []string
+}
+
+// LoadMap reads the map file at path.
+func LoadMap(path string) (*Map, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	m, err := ReadMap(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read map %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// ReadMap reads a map in the Moving AI format, which is a header with the map's type,
+// height and width, followed by the "map" line and the map's rows.
+func ReadMap(r io.Reader) (*Map, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	lineNumber := 0
+	nextLine := func() (string, bool) {
+		lineNumber++
+		if !scanner.Scan() {
+			return "", false
+		}
+		return strings.TrimRight(scanner.Text(), "\r"), true
+	}
+
+	width, height := -1, -1
+	for {
+		line, ok := nextLine()
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing \"map\" line", lineNumber)
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 1 && fields[0] == "map" {
+			break
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: invalid header line: %q", lineNumber, line)
+		}
+
+		var err error
+		switch fields[0] {
+		case "type":
+			if fields[1] != "octile" {
+				return nil, fmt.Errorf(
+					"line %d: unsupported map type: %q", lineNumber, fields[1],
+				)
+			}
+		case "height":
+			height, err = strconv.Atoi(fields[1])
+		case "width":
+			width, err = strconv.Atoi(fields[1])
+		default:
+			return nil, fmt.Errorf("line %d: unknown header: %q", lineNumber, fields[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid %s: %q", lineNumber, fields[0], fields[1])
+		}
+	}
+	if width < 1 || height < 1 {
+		return nil, fmt.Errorf("map has no valid width and height")
+	}
+
+	m := Map{Rows: make([]string, 0, height)}
+	for len(m.Rows) < height {
+		line, ok := nextLine()
+		if !ok {
+			return nil, fmt.Errorf("map has %d rows, expected %d", len(m.Rows), height)
+		}
+		if len(line) != width {
+			return nil, fmt.Errorf(
+				"line %d: row has %d cells, expected %d", lineNumber, len(line), width,
+			)
+		}
+		m.Rows = append(m.Rows, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Width is the amount of columns of the map.
+func (m *Map) Width() int {
+	return len(m.Rows[0])
+}
+
+// Height is the amount of rows of the map.
+func (m *Map) Height() int {
+	return len(m.Rows)
+}
+
+// Passable reports whether the cell at "at" is within the map and may be traversed.
+func (m *Map) Passable(at xy.XY) bool {
+	if at.Y < 0 || at.Y >= len(m.Rows) || at.X < 0 || at.X >= len(m.Rows[at.Y]) {
+		return false
+	}
+	return passable(m.Rows[at.Y][at.X])
+}
+
+func passable(cell byte) bool {
+	return cell == '.' || cell == 'G' || cell == 'S'
+}
+
+// Graph returns the graph formed by the map's cells, which is searched by astar.FindPath
+// along with Heuristic.
+func (m *Map) Graph() *grid.Graph[byte] {
+	cells := make([][]byte, len(m.Rows))
+	for y, row := range m.Rows {
+		cells[y] = []byte(row)
+	}
+	return &grid.Graph[byte]{
+		Grid:         grid.FromRows(cells),
+		Passable:     passable,
+		Cost:         func(byte) int { return StraightCost },
+		Neighborhood: grid.EightConnected,
+		Diagonal:     grid.DiagonalSqrt2,
+		Corners:      grid.CornersNever,
+	}
+}
+
+// Heuristic is the octile distance between two cells of a map's graph.
+var Heuristic = grid.Octile[byte](StraightCost, grid.DiagonalSqrt2)
@@ -0,0 +1,147 @@
+package movingai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+func TestLoadMap(t *testing.T) {
+	m, err := LoadMap("testdata/tiny.map")
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if m.Width() != 8 || m.Height() != 6 {
+		t.Fatalf("Unexpected size: %dx%d", m.Width(), m.Height())
+	}
+
+	cases := map[xy.XY]bool{
+		{X: 0, Y: 0}: true, {X: 2, Y: 1}: false, {X: 4, Y: 2}: false, {X: 5, Y: 3}: false,
+		{X: 1, Y: 4}: true, {X: 3, Y: 4}: true, {X: 8, Y: 0}: false, {X: 0, Y: -1}: false,
+	}
+	for at, want := range cases {
+		if got := m.Passable(at); got != want {
+			t.Errorf("Passable(%v) = %v, expected %v", at, got, want)
+		}
+	}
+
+	graph := m.Graph()
+	path, cost := astar.FindPath(
+		graph.Node(xy.XY{X: 0, Y: 0}), graph.Node(xy.XY{X: 1, Y: 1}), Heuristic,
+	)
+	if len(path) != 2 || cost != 1_414_214 {
+		t.Errorf("Unexpected diagonal path: %d nodes costing %d", len(path), cost)
+	}
+}
+
+func TestReadMapErrors(t *testing.T) {
+	cases := map[string]string{
+		"type tile\nheight 1\nwidth 1\nmap\n.\n":   "unsupported map type",
+		"type octile\nheight 2\nwidth 1\nmap\n.\n": "expected 2",
+		"type octile\nheight 1\nwidth 2\nmap\n.\n": "row has 1 cells",
+		"type octile\nheight 1\nwidth 1\n.\n":      "invalid header",
+		"type octile\nheight x\nwidth 1\nmap\n.\n": "invalid height",
+		"type octile\nheight 1\nwidth 1\n":         "missing \"map\" line",
+		"type octile\nmap\n.\n":                    "no valid width and height",
+		"type octile\ndepth 1\nwidth 1\nmap\n.\n":  "unknown header",
+	}
+	for data, want := range cases {
+		_, err := ReadMap(strings.NewReader(data))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected an error containing %q, got %v", data, want, err)
+		}
+	}
+}
+
+func TestReadScenarios(t *testing.T) {
+	data := "version 1\n" +
+		"0\tmaps/a.map\t4\t3\t0\t1\t2\t1\t2.00000000\n" +
+		"\n" +
+		"1 maps/a.map 4 3 0 0 3 2 3.41421356\n"
+	scenarios, err := ReadScenarios(strings.NewReader(data))
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	want := []Scenario{
+		{
+			Bucket: 0, Map: "maps/a.map", Width: 4, Height: 3,
+			Start: xy.XY{X: 0, Y: 1}, Goal: xy.XY{X: 2, Y: 1}, Optimal: 2,
+		},
+		{
+			Bucket: 1, Map: "maps/a.map", Width: 4, Height: 3,
+			Start: xy.XY{X: 0, Y: 0}, Goal: xy.XY{X: 3, Y: 2}, Optimal: 3.41421356,
+		},
+	}
+	if len(scenarios) != len(want) {
+		t.Fatalf("Expected %d scenarios, got %d", len(want), len(scenarios))
+	}
+	for idx := range want {
+		if scenarios[idx] != want[idx] {
+			t.Errorf("Scenario %d: expected %+v, got %+v", idx, want[idx], scenarios[idx])
+		}
+	}
+
+	for _, data := range [...]string{
+		"",
+		"version 2\n",
+		"version 1\n0\ta.map\t4\t3\t0\t1\t2\t1\n",
+		"version 1\n0\ta.map\t4\t3\tx\t1\t2\t1\t2\n",
+		"version 1\n0\ta.map\t4\t3\t0\t1\t2\t1\tlong\n",
+	} {
+		if _, err := ReadScenarios(strings.NewReader(data)); err == nil {
+			t.Errorf("%q: expected an error", data)
+		}
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	results, err := RunFile("testdata/tiny.map.scen")
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if len(results) != 6 {
+		t.Fatalf("Expected 6 results, got %d", len(results))
+	}
+	scenarios, err := LoadScenarios("testdata/tiny.map.scen")
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	for idx, result := range results {
+		if result.Scenario != scenarios[idx] {
+			t.Errorf("Result %d is for another scenario: %+v", idx, result.Scenario)
+		}
+		if !result.OK() {
+			t.Errorf(
+				"Scenario %d: expected length %f, got %f (found: %v)",
+				idx, result.Scenario.Optimal, result.Length, result.Found,
+			)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	m, err := LoadMap("testdata/tiny.map")
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	wrongSize := Scenario{Width: 4, Height: 4}
+	if _, err := Run(m, []Scenario{wrongSize}); err == nil {
+		t.Error("Expected an error for a scenario of another map size")
+	}
+	blocked := Scenario{Width: 8, Height: 6, Goal: xy.XY{X: 2, Y: 1}}
+	if _, err := Run(m, []Scenario{blocked}); err == nil {
+		t.Error("Expected an error for a scenario ending on an impassable cell")
+	}
+
+	wrongLength := Scenario{Width: 8, Height: 6, Goal: xy.XY{X: 1, Y: 0}, Optimal: 2}
+	results, err := Run(m, []Scenario{wrongLength})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if results[0].OK() {
+		t.Error("Expected a path shorter than the recorded length not to be OK")
+	}
+}
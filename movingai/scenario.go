@@ -0,0 +1,200 @@
+package movingai
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// Tolerance is the relative difference allowed between the length of a path found on a
+// map and the optimal length recorded in a scenario. It accounts for the rounding of
+// diagonal moves and of the recorded lengths.
+const Tolerance = 1e-6
+
+// Scenario is a single problem of a scenario file: finding the shortest path from Start
+// to Goal on a map.
+type Scenario struct {
+	Bucket int
+	// Map is the path of the map's file, as written in the scenario file.
+	Map           string
+	Width, Height int
+	Start, Goal   xy.XY
+	// Optimal is the length of the shortest path.
+	Optimal float64
+}
+
+// LoadScenarios reads the scenario file at path.
+func LoadScenarios(path string) ([]Scenario, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scenarios, err := ReadScenarios(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenarios %s: %w", path, err)
+	}
+	return scenarios, nil
+}
+
+// ReadScenarios reads scenarios in the Moving AI format, which is a "version 1" line
+// followed by a scenario per line.
+func ReadScenarios(r io.Reader) ([]Scenario, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("line 1: missing version")
+	}
+	if version := strings.Fields(scanner.Text()); len(version) != 2 ||
+		version[0] != "version" || (version[1] != "1" && version[1] != "1.0") {
+		return nil, fmt.Errorf("line 1: unsupported version: %q", scanner.Text())
+	}
+
+	var scenarios []Scenario
+	for lineNumber := 2; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 9 {
+			fields = strings.Fields(line)
+		}
+		if len(fields) != 9 {
+			return nil, fmt.Errorf(
+				"line %d: expected 9 fields, found %d", lineNumber, len(fields),
+			)
+		}
+
+		scenario := Scenario{Map: fields[1]}
+		ints := [...]*int{
+			&scenario.Bucket, nil, &scenario.Width, &scenario.Height,
+			&scenario.Start.X, &scenario.Start.Y, &scenario.Goal.X, &scenario.Goal.Y,
+		}
+		for idx, target := range ints {
+			if target == nil {
+				continue
+			}
+			value, err := strconv.Atoi(fields[idx])
+			if err != nil {
+				return nil, fmt.Errorf(
+					"line %d: invalid field %d: %q", lineNumber, idx+1, fields[idx],
+				)
+			}
+			*target = value
+		}
+		optimal, err := strconv.ParseFloat(fields[8], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid optimal length: %q", lineNumber, fields[8])
+		}
+		scenario.Optimal = optimal
+
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, scanner.Err()
+}
+
+// Result is the outcome of searching for the path of a scenario.
+type Result struct {
+	Scenario Scenario
+	// Found reports whether a path was found, and Length is its length.
+	Found  bool
+	Length float64
+	// Expanded is the amount of nodes expanded by the search.
+	Expanded int
+}
+
+// OK reports whether the path found is as long as the scenario's optimal path.
+func (r Result) OK() bool {
+	want := r.Scenario.Optimal
+	return r.Found && math.Abs(r.Length-want) <= Tolerance*math.Max(1, want)
+}
+
+// Run searches for the path of each scenario on m. Errors refer to scenarios by their
+// index in scenarios.
+func Run(m *Map, scenarios []Scenario) ([]Result, error) {
+	graph := m.Graph()
+	results := make([]Result, 0, len(scenarios))
+	for idx, scenario := range scenarios {
+		if scenario.Width != m.Width() || scenario.Height != m.Height() {
+			return nil, fmt.Errorf(
+				"scenario %d is meant for a %dx%d map, but the map is %dx%d",
+				idx, scenario.Width, scenario.Height, m.Width(), m.Height(),
+			)
+		}
+		for _, at := range [...]xy.XY{scenario.Start, scenario.Goal} {
+			if !m.Passable(at) {
+				return nil, fmt.Errorf(
+					"scenario %d: point (%d, %d) is not passable", idx, at.X, at.Y,
+				)
+			}
+		}
+
+		start, goal := graph.Node(scenario.Start), graph.Node(scenario.Goal)
+		searcher := astar.NewSearcher(start, goal, Heuristic)
+		for searcher.Step() {
+		}
+		_, cost := searcher.Path()
+		results = append(results, Result{
+			Scenario: scenario, Found: searcher.Found(),
+			Length: float64(cost) / StraightCost, Expanded: len(searcher.Closed()),
+		})
+	}
+	return results, nil
+}
+
+// RunFile runs every scenario of the scenario file at path. Maps are looked up relative
+// to the scenario file's directory; if a map is not found there, its base name is tried
+// instead, as scenario files often refer to maps by their paths within the benchmark
+// set.
+func RunFile(path string) ([]Result, error) {
+	scenarios, err := LoadScenarios(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// scenarios are grouped by map, so each map's graph is built only once
+	var names []string
+	groups := make(map[string][]int)
+	for idx, scenario := range scenarios {
+		if _, isGrouped := groups[scenario.Map]; !isGrouped {
+			names = append(names, scenario.Map)
+		}
+		groups[scenario.Map] = append(groups[scenario.Map], idx)
+	}
+
+	dir := filepath.Dir(path)
+	results := make([]Result, len(scenarios))
+	for _, name := range names {
+		mapPath := filepath.Join(dir, name)
+		if _, err := os.Stat(mapPath); err != nil {
+			mapPath = filepath.Join(dir, filepath.Base(name))
+		}
+		m, err := LoadMap(mapPath)
+		if err != nil {
+			return nil, err
+		}
+
+		group := make([]Scenario, len(groups[name]))
+		for idx, scenarioIdx := range groups[name] {
+			group[idx] = scenarios[scenarioIdx]
+		}
+		groupResults, err := Run(m, group)
+		if err != nil {
+			return nil, fmt.Errorf("map %s: %w", name, err)
+		}
+		for idx, scenarioIdx := range groups[name] {
+			results[scenarioIdx] = groupResults[idx]
+		}
+	}
+	return results, nil
+}
@@ -34,7 +34,8 @@ func (iq *innerQueue[T]) Pop() item[T] {
 
 var _ heap.Interface[item[any]] = &innerQueue[any]{}
 
-// PriorityQueue is a generic priority queue. It
+// PriorityQueue is a generic priority queue. Its zero value is an empty queue ready to
+// use.
 type PriorityQueue[T any] struct {
 	innerQueue[T]
 }
@@ -51,6 +52,21 @@ func (pq *PriorityQueue[T]) Pop() T {
 	return i.value
 }
 
+// PopLowest removes the lowest priority item from the queue and returns it. It is
+// meant for callers which must keep the queue within a given size.
+func (pq *PriorityQueue[T]) PopLowest() T {
+	// the lowest priority item is always one of the heap's leaves, which are stored in
+	// the second half of the inner queue
+	lowest := len(pq.innerQueue) / 2
+	for idx := lowest + 1; idx < len(pq.innerQueue); idx++ {
+		if pq.innerQueue[idx].priority < pq.innerQueue[lowest].priority {
+			lowest = idx
+		}
+	}
+	i := heap.Remove[item[T]](&pq.innerQueue, lowest)
+	return i.value
+}
+
 // Empty reports whether the queue is empty.
 func (pq *PriorityQueue[T]) Empty() bool {
 	return len(pq.innerQueue) == 0
@@ -20,3 +20,28 @@ func TestPriorityQueue(t *testing.T) {
 		t.Fatal("Expected queue to be empty")
 	}
 }
+
+func TestPopLowest(t *testing.T) {
+	var queue PriorityQueue[string]
+
+	for idx, str := range [...]string{"B value", "D value", "A value", "E value", "C value"} {
+		queue.Push(str, []int{10, 0, 15, -5, 5}[idx])
+	}
+
+	if popped := queue.PopLowest(); popped != "E value" {
+		t.Fatalf("Expected 'E value', got '%s'", popped)
+	}
+	if popped := queue.PopLowest(); popped != "D value" {
+		t.Fatalf("Expected 'D value', got '%s'", popped)
+	}
+	if queue.Len() != 3 {
+		t.Fatalf("Expected 3 remaining values, got %d", queue.Len())
+	}
+
+	for _, str := range [...]string{"A value", "B value", "C value"} {
+		popped := queue.Pop()
+		if popped != str {
+			t.Fatalf("Expected '%s', got '%s'", str, popped)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package world
+
+// Heuristic implements a heuristic on a pair of nodes of the world, which never
+// overestimates the cost of a path as long as the heuristics given to each level do not.
+//
+// Within a single level, the estimate is the level's own estimate. Paths which go
+// through portals are estimated by the cost of reaching the nearest portal which leaves
+// the start's level plus the cost of reaching the goal from the nearest portal which
+// arrives at the goal's level.
+func (w *World) Heuristic(from, to Node) int {
+	exit, entry := w.exitEstimate(from.Location), w.entryEstimate(to.Location)
+	throughPortals := -1
+	if exit >= 0 && entry >= 0 {
+		throughPortals = exit + entry
+	}
+
+	if from.Level != to.Level {
+		if throughPortals < 0 {
+			// there is no path at all, but heuristics have no way to report it
+			return 0
+		}
+		return throughPortals
+	}
+
+	direct := w.levels[from.Level].distance(from.XY, to.XY)
+	if throughPortals >= 0 && throughPortals < direct {
+		return throughPortals
+	}
+	return direct
+}
+
+// exitEstimate returns the estimated cost of reaching the nearest portal which leaves
+// from's level, or -1 if there are no such portals.
+func (w *World) exitEstimate(from Location) int {
+	level, estimate := w.levels[from.Level], -1
+	for portal := range w.portals {
+		if portal.Level != from.Level {
+			continue
+		}
+		if distance := level.distance(from.XY, portal.XY); estimate < 0 || distance < estimate {
+			estimate = distance
+		}
+	}
+	return estimate
+}
+
+// entryEstimate returns the estimated cost of reaching to from the nearest portal
+// destination within to's level, or -1 if there are no such destinations.
+func (w *World) entryEstimate(to Location) int {
+	level, estimate := w.levels[to.Level], -1
+	for _, destinations := range w.portals {
+		for _, destination := range destinations {
+			if destination.Level != to.Level {
+				continue
+			}
+			distance := level.distance(destination.XY, to.XY)
+			if estimate < 0 || distance < estimate {
+				estimate = distance
+			}
+		}
+	}
+	return estimate
+}
@@ -0,0 +1,43 @@
+package world
+
+import "github.com/agstrc/heuristic-search/xy"
+
+// Node implements astar.Node on a location of a World. A node is connected to its
+// neighbors within its level and to the locations its portals lead to. Nodes are only
+// comparable to nodes of the same world.
+type Node struct {
+	Location
+	// world uses a pointer in order to make this struct comparable.
+	world *World
+}
+
+func (n Node) Neighbors() []Node {
+	var neighbors []Node
+	for _, at := range n.level().neighbors(n.XY) {
+		neighbors = append(neighbors, n.world.Node(Location{Level: n.Level, XY: at}))
+	}
+	for _, to := range n.world.portals[n.Location] {
+		neighbors = append(neighbors, n.world.Node(to))
+	}
+	return neighbors
+}
+
+// Cost returns the cost to move into the node.
+func (n Node) Cost() int {
+	return n.level().cost(n.XY)
+}
+
+// StepCost returns the cost to move from the node into next, which must be one of its
+// neighbors. Going through a portal costs as much as moving into its destination.
+func (n Node) StepCost(next Node) int {
+	// portals may only lead to adjacent positions of the same level when they are
+	// redundant, so any farther position is reached through a portal
+	if next.Level != n.Level || xy.Chebyshev(n.XY, next.XY) > 1 {
+		return next.Cost()
+	}
+	return n.level().stepCost(n.XY, next.XY)
+}
+
+func (n Node) level() *Level {
+	return n.world.levels[n.Level]
+}
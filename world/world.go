@@ -0,0 +1,118 @@
+// Package world models worlds made of multiple named grids, called levels, which are
+// connected to each other through portals. A single search may then plan a path which
+// goes through several levels.
+package world
+
+import (
+	"fmt"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// Location is a position within one of the world's levels.
+type Location struct {
+	Level string `json:"level"`
+	xy.XY
+}
+
+// Level is a single grid of a world.
+type Level struct {
+	name string
+
+	// the following functions are derived from the grid graph the level is made of.
+	neighbors func(xy.XY) []xy.XY
+	cost      func(xy.XY) int
+	stepCost  func(from, to xy.XY) int
+	passable  func(xy.XY) bool
+	distance  func(from, to xy.XY) int
+}
+
+// NewLevel returns a level named name which is made of graph. heuristic must never
+// overestimate the cost of a path within the graph, as it is used to estimate the cost
+// of paths within the level.
+func NewLevel[T any](name string, graph *grid.Graph[T], heuristic astar.Heuristic[grid.Node[T]]) *Level {
+	return &Level{
+		name: name,
+		neighbors: func(at xy.XY) []xy.XY {
+			var neighbors []xy.XY
+			for _, node := range graph.Node(at).Neighbors() {
+				neighbors = append(neighbors, node.XY)
+			}
+			return neighbors
+		},
+		cost: func(at xy.XY) int {
+			return graph.Node(at).Cost()
+		},
+		stepCost: func(from, to xy.XY) int {
+			return graph.Node(from).StepCost(graph.Node(to))
+		},
+		passable: func(at xy.XY) bool {
+			return graph.Grid.In(at) && (graph.Passable == nil || graph.Passable(graph.Grid.At(at)))
+		},
+		distance: func(from, to xy.XY) int {
+			return heuristic(graph.Node(from), graph.Node(to))
+		},
+	}
+}
+
+// Name returns the level's name.
+func (l *Level) Name() string {
+	return l.name
+}
+
+// World is a set of levels connected by portals. Its zero value is an empty world ready
+// to use.
+type World struct {
+	levels map[string]*Level
+	// portals maps a location to every location it leads to.
+	portals map[Location][]Location
+}
+
+// AddLevel adds level to the world. It panics if the world already has a level with the
+// same name.
+func (w *World) AddLevel(level *Level) {
+	if w.levels == nil {
+		w.levels = make(map[string]*Level)
+	}
+	if _, exists := w.levels[level.name]; exists {
+		panic(fmt.Sprintf("world already has a level named %q", level.name))
+	}
+	w.levels[level.name] = level
+}
+
+// Level returns the level with the given name, or nil if there is no such level.
+func (w *World) Level(name string) *Level {
+	return w.levels[name]
+}
+
+// Link adds a one way portal which leads from from to to. Both locations must be
+// passable positions of the world's levels, otherwise Link panics.
+func (w *World) Link(from, to Location) {
+	for _, location := range [...]Location{from, to} {
+		if !w.passable(location) {
+			panic(fmt.Sprintf("portal location %v is not passable", location))
+		}
+	}
+	if w.portals == nil {
+		w.portals = make(map[Location][]Location)
+	}
+	w.portals[from] = append(w.portals[from], to)
+}
+
+// Connect adds a portal which leads from a to b and back. See Link.
+func (w *World) Connect(a, b Location) {
+	w.Link(a, b)
+	w.Link(b, a)
+}
+
+// Node returns the node at the given location.
+func (w *World) Node(at Location) Node {
+	return Node{Location: at, world: w}
+}
+
+func (w *World) passable(at Location) bool {
+	level := w.levels[at.Level]
+	return level != nil && level.passable(at.XY)
+}
@@ -0,0 +1,65 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/agstrc/heuristic-search/astar"
+	"github.com/agstrc/heuristic-search/astar/verify"
+	"github.com/agstrc/heuristic-search/grid"
+	"github.com/agstrc/heuristic-search/xy"
+)
+
+// newLevel returns a level made of rows, in which '#' is a wall and any other block
+// costs 1.
+func newLevel(name string, rows ...string) *Level {
+	cells := make([][]byte, len(rows))
+	for idx, row := range rows {
+		cells[idx] = []byte(row)
+	}
+	graph := &grid.Graph[byte]{
+		Grid:     grid.FromRows(cells),
+		Passable: func(b byte) bool { return b != '#' },
+		Cost:     func(byte) int { return 1 },
+	}
+	return NewLevel(name, graph, grid.Manhattan[byte](1))
+}
+
+func TestWorld(t *testing.T) {
+	var w World
+	w.AddLevel(newLevel("surface",
+		"    ",
+		"### ",
+		"    ",
+	))
+	w.AddLevel(newLevel("cellar",
+		"   ",
+		"   ",
+	))
+
+	// the surface's left side is only reachable through the cellar
+	w.Connect(Location{"surface", xy.XY{X: 0, Y: 0}}, Location{"cellar", xy.XY{X: 0, Y: 0}})
+	w.Link(Location{"cellar", xy.XY{X: 2, Y: 1}}, Location{"surface", xy.XY{X: 0, Y: 2}})
+
+	start := w.Node(Location{"surface", xy.XY{X: 0, Y: 0}})
+	goal := w.Node(Location{"surface", xy.XY{X: 0, Y: 2}})
+
+	path, cost := astar.FindPath(start, goal, w.Heuristic)
+	if path == nil {
+		t.Fatal("Expected non nil path")
+	}
+	// down to the cellar, three steps through it and back up
+	if cost != 5 || len(path) != 6 {
+		t.Fatalf("Unexpected path with cost %d and length %d: %v", cost, len(path), path)
+	}
+	if path[1].Level != "cellar" || path[len(path)-2].Level != "cellar" {
+		t.Fatal("Expected path to go through the cellar")
+	}
+
+	// the link is one way, so the way back goes around the wall
+	path, cost = astar.FindPath(goal, start, w.Heuristic)
+	if path[1].Level != "surface" || cost != 8 {
+		t.Fatalf("Unexpected path back with cost %d: %v", cost, path)
+	}
+
+	verify.Heuristic(t, w.Heuristic, start)
+}
@@ -0,0 +1,52 @@
+package xy
+
+// Direction is one of the eight directions around a point, ordered clockwise from
+// North. North points towards lower values of Y.
+type Direction int
+
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+var (
+	// Directions4 are the directions which are not diagonal.
+	Directions4 = [4]Direction{North, East, South, West}
+	// Directions8 are all directions.
+	Directions8 = [8]Direction{North, NorthEast, East, SouthEast, South, SouthWest, West, NorthWest}
+)
+
+var directionOffsets = [8]XY{
+	{X: 0, Y: -1}, {X: 1, Y: -1}, {X: 1, Y: 0}, {X: 1, Y: 1},
+	{X: 0, Y: 1}, {X: -1, Y: 1}, {X: -1, Y: 0}, {X: -1, Y: -1},
+}
+
+var directionNames = [8]string{
+	"north", "northeast", "east", "southeast", "south", "southwest", "west", "northwest",
+}
+
+// Offset returns the offset of a single step in the direction.
+func (d Direction) Offset() XY {
+	return directionOffsets[d]
+}
+
+// Rotate returns the direction rotated clockwise by steps eighths of a turn. Negative
+// steps rotate it counterclockwise.
+func (d Direction) Rotate(steps int) Direction {
+	return Direction(((int(d)+steps)%8 + 8) % 8)
+}
+
+// Diagonal reports whether the direction is diagonal.
+func (d Direction) Diagonal() bool {
+	return d%2 == 1
+}
+
+func (d Direction) String() string {
+	return directionNames[d]
+}
@@ -0,0 +1,72 @@
+package xy
+
+// This file exports coordinates for grids of pointy-top hexagons. Hexagonal grids are
+// stored as rows of cells in the "odd-r" offset layout, in which odd rows are shoved
+// half a cell to the right. XY is used for such offset coordinates, while Axial and
+// Cube make the hexagonal arithmetic simpler.
+
+// Axial is a pair of axial hexagonal coordinates. Q grows towards the right and R grows
+// towards the bottom right.
+type Axial struct {
+	Q int `json:"q"`
+	R int `json:"r"`
+}
+
+// Cube is a triple of cube hexagonal coordinates, whose sum is always zero.
+type Cube struct {
+	Q int `json:"q"`
+	R int `json:"r"`
+	S int `json:"s"`
+}
+
+// HexDirections are the offsets from an axial coordinate to its six neighbors, starting
+// at the right and going clockwise.
+var HexDirections = [6]Axial{
+	{Q: 1, R: 0}, {Q: 0, R: 1}, {Q: -1, R: 1},
+	{Q: -1, R: 0}, {Q: 0, R: -1}, {Q: 1, R: -1},
+}
+
+// Cube converts axial coordinates to cube coordinates.
+func (a Axial) Cube() Cube {
+	return Cube{Q: a.Q, R: a.R, S: -a.Q - a.R}
+}
+
+// Axial converts cube coordinates to axial coordinates.
+func (c Cube) Axial() Axial {
+	return Axial{Q: c.Q, R: c.R}
+}
+
+// OddR converts axial coordinates to offset coordinates in the odd-r layout.
+func (a Axial) OddR() XY {
+	return XY{X: a.Q + (a.R-(a.R&1))/2, Y: a.R}
+}
+
+// OddRToAxial converts offset coordinates in the odd-r layout to axial coordinates.
+func OddRToAxial(at XY) Axial {
+	return Axial{Q: at.X - (at.Y-(at.Y&1))/2, R: at.Y}
+}
+
+// Neighbors returns the six neighbors of a, in the same order as HexDirections.
+func (a Axial) Neighbors() [6]Axial {
+	var neighbors [6]Axial
+	for idx, direction := range HexDirections {
+		neighbors[idx] = Axial{Q: a.Q + direction.Q, R: a.R + direction.R}
+	}
+	return neighbors
+}
+
+// HexDistance returns the amount of steps between two hexagons.
+func HexDistance(from, to Axial) int {
+	f, t := from.Cube(), to.Cube()
+	qDistance, rDistance, sDistance := f.Q-t.Q, f.R-t.R, f.S-t.S
+	if qDistance < 0 {
+		qDistance = -qDistance
+	}
+	if rDistance < 0 {
+		rDistance = -rDistance
+	}
+	if sDistance < 0 {
+		sDistance = -sDistance
+	}
+	return (qDistance + rDistance + sDistance) / 2
+}
@@ -0,0 +1,35 @@
+package xy
+
+import "testing"
+
+func TestHex(t *testing.T) {
+	for y := -3; y <= 3; y++ {
+		for x := -3; x <= 3; x++ {
+			at := XY{X: x, Y: y}
+			if back := OddRToAxial(at).OddR(); back != at {
+				t.Fatalf("Expected %v to convert back to itself, got %v", at, back)
+			}
+		}
+	}
+
+	origin := Axial{Q: 0, R: 0}
+	if cube := (Axial{Q: 2, R: -3}).Cube(); cube != (Cube{Q: 2, R: -3, S: 1}) || cube.Axial() != (Axial{Q: 2, R: -3}) {
+		t.Fatal("Unexpected cube conversion")
+	}
+	for _, neighbor := range origin.Neighbors() {
+		if HexDistance(origin, neighbor) != 1 {
+			t.Fatalf("Expected %v to be adjacent to the origin", neighbor)
+		}
+	}
+	if distance := HexDistance(origin, Axial{Q: 3, R: -1}); distance != 3 {
+		t.Fatalf("Expected distance 3, got %d", distance)
+	}
+
+	// on odd-r rows, (1, 1) is below and to the right of (1, 0) and (2, 0)
+	below := OddRToAxial(XY{X: 1, Y: 1})
+	for _, above := range [...]XY{{X: 1, Y: 0}, {X: 2, Y: 0}} {
+		if HexDistance(below, OddRToAxial(above)) != 1 {
+			t.Fatalf("Expected %v to be adjacent to (1, 1)", above)
+		}
+	}
+}
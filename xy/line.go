@@ -0,0 +1,33 @@
+package xy
+
+// Line returns the points of the line from from to to, both included, as rasterized by
+// Bresenham's algorithm. Consecutive points are adjacent, possibly diagonally.
+func Line(from, to XY) []XY {
+	d := to.Sub(from)
+	dx, dy := abs(d.X), -abs(d.Y)
+	sx, sy := 1, 1
+	if d.X < 0 {
+		sx = -1
+	}
+	if d.Y < 0 {
+		sy = -1
+	}
+
+	points := make([]XY, 0, Chebyshev(from, to)+1)
+	err := dx + dy
+	for p := from; ; {
+		points = append(points, p)
+		if p == to {
+			return points
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			err += dx
+			p.Y += sy
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package xy
+
+// Rect is a rectangle of points. It contains every point from Min up to, but not
+// including, Max.
+type Rect struct {
+	Min XY `json:"min"`
+	Max XY `json:"max"`
+}
+
+// RectOf returns the rectangle with its top left corner at the origin and the given
+// size.
+func RectOf(width, height int) Rect {
+	return Rect{Max: XY{X: width, Y: height}}
+}
+
+// Width returns the rectangle's amount of columns.
+func (r Rect) Width() int {
+	return r.Max.X - r.Min.X
+}
+
+// Height returns the rectangle's amount of rows.
+func (r Rect) Height() int {
+	return r.Max.Y - r.Min.Y
+}
+
+// Empty reports whether the rectangle contains no points.
+func (r Rect) Empty() bool {
+	return r.Min.X >= r.Max.X || r.Min.Y >= r.Max.Y
+}
+
+// Contains reports whether p is within the rectangle.
+func (r Rect) Contains(p XY) bool {
+	return p.X >= r.Min.X && p.X < r.Max.X && p.Y >= r.Min.Y && p.Y < r.Max.Y
+}
+
+// Clip returns the intersection of r and s. If they do not intersect, the returned
+// rectangle is empty.
+func (r Rect) Clip(s Rect) Rect {
+	if s.Min.X > r.Min.X {
+		r.Min.X = s.Min.X
+	}
+	if s.Min.Y > r.Min.Y {
+		r.Min.Y = s.Min.Y
+	}
+	if s.Max.X < r.Max.X {
+		r.Max.X = s.Max.X
+	}
+	if s.Max.Y < r.Max.Y {
+		r.Max.Y = s.Max.Y
+	}
+	if r.Empty() {
+		return Rect{}
+	}
+	return r
+}
+
+// Iterate calls f for every point of the rectangle, row by row. If f returns false, the
+// iteration stops.
+func (r Rect) Iterate(f func(XY) bool) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if !f(XY{X: x, Y: y}) {
+				return
+			}
+		}
+	}
+}
@@ -1,8 +1,108 @@
-// Package xy exports a simple coordinate pair definition to the rest of the project.
+// Package xy exports a simple coordinate pair definition to the rest of the project,
+// along with the geometry built on top of it.
 package xy
 
-// XY is a simple coordinates representation.
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// XY is a simple coordinates representation. Y grows downwards, as rows do on a grid.
 type XY struct {
 	X int `json:"x"`
-	Y int `json:"Y"`
+	Y int `json:"y"`
+}
+
+// UnmarshalJSON decodes a coordinate pair. Unlike encoding/json's default matching, its
+// keys are case sensitive, so the uppercase "Y" of older plan files is rejected; those
+// files are migrated by the plan package before they are decoded.
+func (p *XY) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key := range fields {
+		isCoordinate := strings.EqualFold(key, "x") || strings.EqualFold(key, "y")
+		if isCoordinate && key != "x" && key != "y" {
+			return fmt.Errorf("coordinate key %q must be lowercase", key)
+		}
+	}
+
+	// pair has the fields of XY but not its methods, so decoding it does not recurse
+	type pair XY
+	return json.Unmarshal(data, (*pair)(p))
+}
+
+// Add returns the sum of p and q.
+func (p XY) Add(q XY) XY {
+	return XY{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// Sub returns the difference between p and q.
+func (p XY) Sub(q XY) XY {
+	return XY{X: p.X - q.X, Y: p.Y - q.Y}
+}
+
+// Scale returns p with both coordinates multiplied by k.
+func (p XY) Scale(k int) XY {
+	return XY{X: p.X * k, Y: p.Y * k}
+}
+
+// Neighbors4 returns the four points above, below and to the sides of p, in the same
+// order as Directions4.
+func (p XY) Neighbors4() [4]XY {
+	var neighbors [4]XY
+	for idx, direction := range Directions4 {
+		neighbors[idx] = p.Add(direction.Offset())
+	}
+	return neighbors
+}
+
+// Neighbors8 returns the eight points around p, in the same order as Directions8.
+func (p XY) Neighbors8() [8]XY {
+	var neighbors [8]XY
+	for idx, direction := range Directions8 {
+		neighbors[idx] = p.Add(direction.Offset())
+	}
+	return neighbors
+}
+
+// Manhattan returns the Manhattan distance between two points, which is the amount of
+// steps between them when moving only horizontally and vertically.
+func Manhattan(from, to XY) int {
+	d := to.Sub(from)
+	return abs(d.X) + abs(d.Y)
+}
+
+// Chebyshev returns the Chebyshev distance between two points, which is the amount of
+// steps between them when moving diagonally is also allowed.
+func Chebyshev(from, to XY) int {
+	d := to.Sub(from)
+	if abs(d.X) > abs(d.Y) {
+		return abs(d.X)
+	}
+	return abs(d.Y)
+}
+
+// Euclidean returns the straight line distance between two points.
+func Euclidean(from, to XY) float64 {
+	d := to.Sub(from)
+	return math.Hypot(float64(d.X), float64(d.Y))
+}
+
+// Octile returns the octile distance between two points, which is the length of the
+// shortest path between them when diagonal steps are √2 long.
+func Octile(from, to XY) float64 {
+	diagonal := Manhattan(from, to) - Chebyshev(from, to)
+	straight := Chebyshev(from, to) - diagonal
+	return float64(straight) + float64(diagonal)*math.Sqrt2
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
 }
@@ -0,0 +1,113 @@
+package xy
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	var p XY
+	if err := json.Unmarshal([]byte(`{"x": 3, "y": 4}`), &p); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if p != (XY{X: 3, Y: 4}) {
+		t.Fatalf("Unexpected decoding: %v", p)
+	}
+	for _, data := range [...]string{`{"x": 3, "Y": 4}`, `{"X": 3, "y": 4}`} {
+		if err := json.Unmarshal([]byte(data), &p); err == nil {
+			t.Fatalf("Expected uppercase keys of %s to be rejected", data)
+		}
+	}
+
+	data, err := json.Marshal(XY{X: 3, Y: 4})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if string(data) != `{"x":3,"y":4}` {
+		t.Fatalf("Unexpected encoding: %s", data)
+	}
+}
+
+func TestDistances(t *testing.T) {
+	from, to := XY{X: 1, Y: 1}, XY{X: 4, Y: -1}
+
+	if to.Sub(from) != (XY{X: 3, Y: -2}) || from.Add(to) != (XY{X: 5, Y: 0}) {
+		t.Fatal("Unexpected arithmetic")
+	}
+	if from.Scale(-2) != (XY{X: -2, Y: -2}) {
+		t.Fatal("Unexpected scaling")
+	}
+	if Manhattan(from, to) != 5 || Chebyshev(from, to) != 3 {
+		t.Fatal("Unexpected integer distances")
+	}
+	if math.Abs(Euclidean(from, to)-math.Sqrt(13)) > 1e-9 {
+		t.Fatal("Unexpected euclidean distance")
+	}
+	if math.Abs(Octile(from, to)-(1+2*math.Sqrt2)) > 1e-9 {
+		t.Fatal("Unexpected octile distance")
+	}
+}
+
+func TestDirection(t *testing.T) {
+	if North.Rotate(2) != East || North.Rotate(-1) != NorthWest || West.Rotate(10) != North {
+		t.Fatal("Unexpected rotation")
+	}
+	if !SouthEast.Diagonal() || South.Diagonal() || SouthEast.String() != "southeast" {
+		t.Fatal("Unexpected direction details")
+	}
+
+	p := XY{X: 5, Y: 5}
+	if p.Neighbors4() != [4]XY{{X: 5, Y: 4}, {X: 6, Y: 5}, {X: 5, Y: 6}, {X: 4, Y: 5}} {
+		t.Fatal("Unexpected neighbors")
+	}
+	for _, neighbor := range p.Neighbors8() {
+		if Chebyshev(p, neighbor) != 1 {
+			t.Fatalf("Expected %v to be a neighbor of %v", neighbor, p)
+		}
+	}
+}
+
+func TestRect(t *testing.T) {
+	r := Rect{Min: XY{X: 1, Y: 1}, Max: XY{X: 4, Y: 3}}
+	if r.Width() != 3 || r.Height() != 2 {
+		t.Fatal("Unexpected dimensions")
+	}
+	if !r.Contains(XY{X: 3, Y: 2}) || r.Contains(XY{X: 4, Y: 2}) || r.Contains(XY{X: 0, Y: 1}) {
+		t.Fatal("Unexpected containment")
+	}
+
+	if clipped := r.Clip(RectOf(2, 2)); clipped != (Rect{Min: XY{X: 1, Y: 1}, Max: XY{X: 2, Y: 2}}) {
+		t.Fatalf("Unexpected clip: %v", clipped)
+	}
+	if !r.Clip(Rect{Min: XY{X: 5, Y: 5}, Max: XY{X: 6, Y: 6}}).Empty() {
+		t.Fatal("Expected empty clip")
+	}
+
+	var points []XY
+	r.Iterate(func(p XY) bool {
+		points = append(points, p)
+		return len(points) < 4
+	})
+	want := []XY{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 1, Y: 2}}
+	if !reflect.DeepEqual(points, want) {
+		t.Fatalf("Unexpected iteration: %v", points)
+	}
+}
+
+func TestLine(t *testing.T) {
+	for _, test := range []struct {
+		from, to XY
+		want     []XY
+	}{
+		{XY{X: 0, Y: 0}, XY{X: 0, Y: 0}, []XY{{X: 0, Y: 0}}},
+		{XY{X: 0, Y: 0}, XY{X: 3, Y: 0}, []XY{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}}},
+		{XY{X: 0, Y: 0}, XY{X: -2, Y: 2}, []XY{{X: 0, Y: 0}, {X: -1, Y: 1}, {X: -2, Y: 2}}},
+		{XY{X: 0, Y: 0}, XY{X: 4, Y: 2}, []XY{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 2}, {X: 4, Y: 2}}},
+	} {
+		if line := Line(test.from, test.to); !reflect.DeepEqual(line, test.want) {
+			t.Fatalf("Unexpected line from %v to %v: %v", test.from, test.to, line)
+		}
+	}
+}